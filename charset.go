@@ -0,0 +1,218 @@
+package legit
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// UnsupportedCharsetError is returned when a body declares a charset which
+// cannot be transcoded to UTF-8
+type UnsupportedCharsetError struct {
+	Charset string
+}
+
+// returns the string representation of the unsupported charset
+func (ue *UnsupportedCharsetError) Error() string {
+	return fmt.Sprintf("unsupported charset %q", ue.Charset)
+}
+
+// transcode returns a reader of r transcoded to UTF-8 from charset. When no
+// charset is declared, UTF-16 is detected by its byte order mark. converted is
+// false if the encoding of r is unknown, and it should be assumed to be UTF-8.
+func transcode(r io.Reader, charset string) (rd io.Reader, converted bool, err error) {
+	if charset != "" {
+		rd, err = charsetReader(charset, r)
+		return rd, err == nil, err
+	}
+
+	br := bufio.NewReader(r)
+	if bom, _ := br.Peek(2); bytes.Equal(bom, []byte{0xFE, 0xFF}) || bytes.Equal(bom, []byte{0xFF, 0xFE}) {
+		return newUTF16Reader(br, false), true, nil
+	}
+
+	return skipBOM(br), false, nil
+}
+
+// transcodeString returns a string transcoded to UTF-8 from charset
+func transcodeString(s, charset string) (string, error) {
+	r, err := charsetReader(charset, strings.NewReader(s))
+	if err != nil {
+		return "", err
+	}
+
+	b, err := ioutil.ReadAll(r)
+	return string(b), err
+}
+
+// charsetReader returns a reader of r transcoded to UTF-8 from a charset label,
+// such as "ISO-8859-1", or an UnsupportedCharsetError. It is suitable for use
+// as xml.Decoder.CharsetReader.
+func charsetReader(label string, r io.Reader) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(label)) {
+	case "utf-8", "utf8", "unicode-1-1-utf-8":
+		return skipBOM(bufio.NewReader(r)), nil
+	case "us-ascii", "ascii":
+		return r, nil
+	case "iso-8859-1", "iso8859-1", "iso_8859-1", "latin1", "l1", "cp819", "ibm819":
+		return newSingleByteReader(r, &latin1), nil
+	case "windows-1252", "cp1252", "x-cp1252":
+		return newSingleByteReader(r, &windows1252), nil
+	case "iso-8859-15", "iso8859-15", "iso_8859-15", "latin-9", "latin9", "l9":
+		return newSingleByteReader(r, &latin9), nil
+	case "utf-16":
+		return newUTF16Reader(r, false), nil
+	case "utf-16be":
+		return newUTF16Reader(r, false), nil
+	case "utf-16le":
+		return newUTF16Reader(r, true), nil
+	}
+
+	return nil, &UnsupportedCharsetError{Charset: label}
+}
+
+// returns a reader discarding the UTF-8 byte order mark, if present
+func skipBOM(br *bufio.Reader) io.Reader {
+	if bom, _ := br.Peek(3); bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		br.Discard(3)
+	}
+
+	return br
+}
+
+// decodeReader transcodes a reader to UTF-8 using a decode function, which
+// returns the UTF-8 encoding of src and the number of bytes of src consumed
+type decodeReader struct {
+	r      io.Reader
+	decode func(dst, src []byte, atEOF bool) ([]byte, int)
+
+	buf []byte // read from r but not yet decoded
+	out []byte // decoded but not yet read
+	err error
+}
+
+func (d *decodeReader) Read(p []byte) (int, error) {
+	for len(d.out) < 1 {
+		if d.err != nil {
+			return 0, d.err
+		}
+
+		chunk := make([]byte, 4096)
+		n, err := d.r.Read(chunk)
+		d.buf = append(d.buf, chunk[:n]...)
+		d.err = err
+
+		out, consumed := d.decode(d.out[:0], d.buf, err != nil)
+		d.out = out
+		d.buf = d.buf[consumed:]
+	}
+
+	n := copy(p, d.out)
+	d.out = d.out[n:]
+	return n, nil
+}
+
+// returns a reader transcoding a single byte encoding to UTF-8
+func newSingleByteReader(r io.Reader, table *[256]rune) io.Reader {
+	return &decodeReader{r: r, decode: func(dst, src []byte, atEOF bool) ([]byte, int) {
+		for _, b := range src {
+			dst = utf8.AppendRune(dst, table[b])
+		}
+		return dst, len(src)
+	}}
+}
+
+// returns a reader transcoding UTF-16 to UTF-8, in big-endian byte order
+// unless a byte order mark is present or littleEndian is set
+func newUTF16Reader(r io.Reader, littleEndian bool) io.Reader {
+	first := true
+
+	return &decodeReader{r: r, decode: func(dst, src []byte, atEOF bool) ([]byte, int) {
+		i := 0
+
+		if first && len(src) >= 2 {
+			first = false
+			switch {
+			case src[0] == 0xFE && src[1] == 0xFF:
+				littleEndian = false
+				i = 2
+			case src[0] == 0xFF && src[1] == 0xFE:
+				littleEndian = true
+				i = 2
+			}
+		}
+
+		unit := func(j int) rune {
+			if littleEndian {
+				return rune(src[j]) | rune(src[j+1])<<8
+			}
+			return rune(src[j])<<8 | rune(src[j+1])
+		}
+
+		for ; i+1 < len(src); i += 2 {
+			r1 := unit(i)
+			if !utf16.IsSurrogate(r1) {
+				dst = utf8.AppendRune(dst, r1)
+				continue
+			}
+
+			// wait for the second half of a surrogate pair
+			if i+3 >= len(src) {
+				if !atEOF {
+					break
+				}
+				dst = utf8.AppendRune(dst, utf8.RuneError)
+				continue
+			}
+
+			if r := utf16.DecodeRune(r1, unit(i+2)); r != utf8.RuneError {
+				dst = utf8.AppendRune(dst, r)
+				i += 2
+			} else {
+				dst = utf8.AppendRune(dst, utf8.RuneError)
+			}
+		}
+
+		// an odd byte at the end of the input is invalid
+		if atEOF && i < len(src) {
+			dst = utf8.AppendRune(dst, utf8.RuneError)
+			i = len(src)
+		}
+
+		return dst, i
+	}}
+}
+
+var latin1, windows1252, latin9 [256]rune
+
+func init() {
+	for i := range latin1 {
+		latin1[i] = rune(i)
+	}
+
+	windows1252 = latin1
+	for b, r := range map[byte]rune{
+		0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„',
+		0x85: '…', 0x86: '†', 0x87: '‡', 0x88: 'ˆ',
+		0x89: '‰', 0x8A: 'Š', 0x8B: '‹', 0x8C: 'Œ',
+		0x8E: 'Ž', 0x91: '‘', 0x92: '’', 0x93: '“',
+		0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—',
+		0x98: '˜', 0x99: '™', 0x9A: 'š', 0x9B: '›',
+		0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+	} {
+		windows1252[b] = r
+	}
+
+	latin9 = latin1
+	for b, r := range map[byte]rune{
+		0xA4: '€', 0xA6: 'Š', 0xA8: 'š', 0xB4: 'Ž',
+		0xB8: 'ž', 0xBC: 'Œ', 0xBD: 'œ', 0xBE: 'Ÿ',
+	} {
+		latin9[b] = r
+	}
+}
@@ -0,0 +1,163 @@
+package legit
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCharsetReader(t *testing.T) {
+	tests := []struct {
+		Charset string
+		Input   string
+		Output  string
+	}{
+		{"utf-8", "\xEF\xBB\xBFcafé", "café"},
+		{"US-ASCII", "cafe", "cafe"},
+		{"ISO-8859-1", "caf\xE9 \x80", "café \u0080"},
+		{"latin1", "\xA4", "¤"},
+		{"windows-1252", "\x80 \x93x\x94 \x81", "€ “x” \u0081"},
+		{"ISO-8859-15", "\xA4 \xBD \xE9", "€ œ é"},
+		{"UTF-16", "\xFE\xFF\x00c\x00\xE9", "cé"},
+		{"utf-16", "\xFF\xFEc\x00\xE9\x00", "cé"},
+		{"utf-16", "\x00c\x00\xE9", "cé"},
+		{"UTF-16LE", "c\x00\x3D\xD8\x00\xDE", "c😀"},
+		{"UTF-16BE", "\x00c\xD8\x3D\xDE\x00", "c😀"},
+		{"UTF-16BE", "\xD8\x3D\x00c", "�c"},
+		{"UTF-16BE", "\x00c\xD8\x3D", "c�"},
+		{"UTF-16BE", "\x00c\x00", "c�"},
+	}
+
+	for _, test := range tests {
+		r, err := charsetReader(test.Charset, iotest.OneByteReader(strings.NewReader(test.Input)))
+		if !assert.NoError(t, err, test.Charset) {
+			continue
+		}
+
+		b, err := ioutil.ReadAll(iotest.OneByteReader(r))
+		if assert.NoError(t, err, test.Charset) {
+			assert.Equal(t, test.Output, string(b), "%s %q", test.Charset, test.Input)
+		}
+	}
+
+	_, err := charsetReader("EBCDIC", strings.NewReader(""))
+	assert.Equal(t, &UnsupportedCharsetError{Charset: "EBCDIC"}, err)
+	assert.EqualError(t, err, `unsupported charset "EBCDIC"`)
+}
+
+func TestTranscode(t *testing.T) {
+	tests := []struct {
+		Charset   string
+		Input     string
+		Output    string
+		Converted bool
+	}{
+		{"", "café", "café", false},
+		{"", "\xEF\xBB\xBFcafé", "café", false},
+		{"", "\xFF\xFEc\x00\xE9\x00", "cé", true},
+		{"", "\xFE\xFF\x00c\x00\xE9", "cé", true},
+		{"iso-8859-1", "caf\xE9", "café", true},
+	}
+
+	for _, test := range tests {
+		r, converted, err := transcode(strings.NewReader(test.Input), test.Charset)
+		if assert.NoError(t, err) {
+			b, _ := ioutil.ReadAll(r)
+			assert.Equal(t, test.Output, string(b))
+			assert.Equal(t, test.Converted, converted)
+		}
+	}
+
+	_, _, err := transcode(strings.NewReader(""), "koi8-r")
+	assert.Equal(t, &UnsupportedCharsetError{Charset: "koi8-r"}, err)
+}
+
+type charsetUser struct {
+	XMLName xml.Name `json:"-" xml:"user"`
+	Name    string   `json:"name" xml:"name" form:"name"`
+}
+
+func TestJSON_DecodeParams_charset(t *testing.T) {
+	var u charsetUser
+	err := JSON{}.DecodeParams(strings.NewReader("{\"name\":\"caf\xE9\"}"), map[string]string{"charset": "ISO-8859-1"}, &u)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "café", u.Name)
+	}
+
+	u = charsetUser{}
+	err = JSON{}.Decode(strings.NewReader("\xFF\xFE{\x00}\x00"), &u)
+	assert.NoError(t, err)
+
+	err = JSON{}.DecodeParams(strings.NewReader("{}"), map[string]string{"charset": "koi8-r"}, &u)
+	assert.Equal(t, &UnsupportedCharsetError{Charset: "koi8-r"}, err)
+}
+
+func TestXML_DecodeParams_charset(t *testing.T) {
+	utf16 := func(s string) string {
+		var sb strings.Builder
+		sb.WriteString("\xFF\xFE")
+		for i := 0; i < len(s); i++ {
+			sb.WriteByte(s[i])
+			sb.WriteByte(0)
+		}
+		return sb.String()
+	}
+
+	tests := []struct {
+		Name   string
+		Params map[string]string
+		Body   string
+	}{
+		{"Prolog", nil, "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><user><name>caf\xE9</name></user>"},
+		{"Param", map[string]string{"charset": "windows-1252"}, "<user><name>caf\xE9</name></user>"},
+		{"ParamAndProlog", map[string]string{"charset": "iso-8859-1"}, "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><user><name>caf\xE9</name></user>"},
+		{"UTF16", nil, utf16("<?xml version=\"1.0\" encoding=\"UTF-16\"?><user><name>caf\xE9</name></user>")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var u charsetUser
+			err := XML{Limits: Limits{MaxStringLength: 4}}.DecodeParams(strings.NewReader(test.Body), test.Params, &u)
+			if assert.NoError(t, err) {
+				assert.Equal(t, "café", u.Name)
+			}
+		})
+	}
+
+	var u charsetUser
+	err := XML{}.Decode(strings.NewReader(`<?xml version="1.0" encoding="koi8-r"?><user/>`), &u)
+	assert.Equal(t, &UnsupportedCharsetError{Charset: "koi8-r"}, err)
+
+	err = XML{}.DecodeParams(strings.NewReader(`<user/>`), map[string]string{"charset": "koi8-r"}, &u)
+	assert.Equal(t, &UnsupportedCharsetError{Charset: "koi8-r"}, err)
+}
+
+func TestURLEncoded_DecodeParams_charset(t *testing.T) {
+	var u charsetUser
+	err := URLEncoded{}.DecodeParams(strings.NewReader("name=caf%E9"), map[string]string{"charset": "ISO-8859-1"}, &u)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "café", u.Name)
+	}
+
+	err = URLEncoded{}.DecodeParams(strings.NewReader(""), map[string]string{"charset": "koi8-r"}, &u)
+	assert.Equal(t, &UnsupportedCharsetError{Charset: "koi8-r"}, err)
+}
+
+func TestForm_ParseAndValidate_charset(t *testing.T) {
+	f := NewForm()
+	f.Decoders = Decoders{JSON{}, XML{}}
+
+	var u charsetUser
+	err := f.ParseAndValidate(strings.NewReader("<user><name>caf\xE9</name></user>"), "text/xml; charset=iso-8859-1", &u)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "café", u.Name)
+	}
+
+	err = f.ParseAndValidate(strings.NewReader("{}"), "application/json; charset=koi8-r", &u)
+	assert.Equal(t, http.StatusUnsupportedMediaType, NewProblem(err).Status)
+}
@@ -0,0 +1,158 @@
+package legit
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrDecodeType is reported when a value in a body does not match the
+	// type of its field.
+	ErrDecodeType = NewValidationError("decode.type", "expected {type}", nil)
+
+	// ErrDecodeSyntax is reported when a body is malformed.
+	ErrDecodeSyntax = NewValidationError("decode.syntax", "malformed body", nil)
+
+	// ErrDecodeEmpty is reported when a body is empty.
+	ErrDecodeEmpty = NewValidationError("decode.empty", "body is empty", nil)
+
+	// ErrDecodeTruncated is reported when a body ends before it is complete.
+	ErrDecodeTruncated = NewValidationError("decode.truncated", "body ends unexpectedly", nil)
+
+	// ErrDecodeUnknownField is reported when a body contains a field not
+	// known to the destination.
+	ErrDecodeUnknownField = NewValidationError("decode.unknown_field", "unknown field", nil)
+
+	// ErrDecodeDuplicateKey is reported when an object in a body contains the
+	// same key more than once.
+	ErrDecodeDuplicateKey = NewValidationError("decode.duplicate_key", "duplicate key", nil)
+
+	// ErrDecodeTrailingData is reported when data follows the end of a body.
+	ErrDecodeTrailingData = NewValidationError("decode.trailing_data", "unexpected data after body", nil)
+)
+
+// decodeErrors returns the failure of a decoder as validation errors located
+// at the field which failed, or nil if the failure is not understood
+func decodeErrors(err error) Errors {
+	var (
+		ute *json.UnmarshalTypeError
+		jse *json.SyntaxError
+		xse *xml.SyntaxError
+		ufe *UnknownFieldError
+		dke *DuplicateKeyError
+		tde *TrailingDataError
+		be  *BindError
+		le  *LimitError
+		uee *UnknownElementError
+	)
+
+	switch {
+	case errors.As(err, &ute):
+		return located(fieldPath(ute.Field, "."), withParams(ErrDecodeType, map[string]interface{}{
+			"type":   typeName(ute.Type),
+			"value":  ute.Value,
+			"offset": ute.Offset,
+		}))
+	case errors.As(err, &jse):
+		return located(nil, withParams(ErrDecodeSyntax, map[string]interface{}{"offset": jse.Offset}))
+	case errors.As(err, &xse):
+		return located(nil, withParams(ErrDecodeSyntax, map[string]interface{}{"line": xse.Line}))
+	case errors.As(err, &ufe):
+		return located(Path{ufe.Field}, ErrDecodeUnknownField)
+	case errors.As(err, &uee):
+		return located(uee.Path, ErrDecodeUnknownField)
+	case errors.As(err, &dke):
+		return located(dke.Path, withParams(ErrDecodeDuplicateKey, map[string]interface{}{"offset": dke.Offset}))
+	case errors.As(err, &tde):
+		return located(nil, withParams(ErrDecodeTrailingData, map[string]interface{}{"offset": tde.Offset}))
+	case errors.As(err, &le):
+		return located(le.Path, withParams(le.Err, map[string]interface{}{"max": le.Max}))
+	case errors.As(err, &be):
+		return located(fieldPath(be.Key, "."), withParams(ErrDecodeType, map[string]interface{}{
+			"type":  be.Type,
+			"value": be.Value,
+		}))
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return located(nil, ErrDecodeTruncated)
+	case errors.Is(err, io.EOF):
+		return located(nil, ErrDecodeEmpty)
+	}
+
+	return nil
+}
+
+// returns a copy of a ValidationError with parameters
+func withParams(ve *ValidationError, params map[string]interface{}) *ValidationError {
+	return NewValidationError(ve.Code, ve.Message, params)
+}
+
+// returns the path of a dotted field name, with numeric elements as indexes
+func fieldPath(field, sep string) Path {
+	if field == "" {
+		return nil
+	}
+
+	var path Path
+	for _, part := range strings.Split(field, sep) {
+		if i, err := strconv.Atoi(part); err == nil {
+			path = append(path, i)
+		} else {
+			path = append(path, part)
+		}
+	}
+
+	return path
+}
+
+// returns an error nested within the StructError and SliceError of each
+// element of a path, as returned by validation
+func located(path Path, err error) Errors {
+	for i := len(path) - 1; i >= 0; i-- {
+		if i < len(path)-1 {
+			err = Errors{err}
+		}
+
+		switch e := path[i].(type) {
+		case int:
+			err = SliceError{Index: e, Message: err}
+		default:
+			err = StructError{Field: fmt.Sprint(e), Message: err}
+		}
+	}
+
+	return Errors{err}
+}
+
+// returns the name of the type expected by a field, as named by JSON
+func typeName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if reflect.PtrTo(t).Implements(textUnmarshaler) {
+		return "string"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+
+	return t.String()
+}
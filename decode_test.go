@@ -0,0 +1,122 @@
+package legit
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type decodeAddress struct {
+	City string `json:"city"`
+}
+
+type decodeUser struct {
+	Age       int             `json:"age"`
+	Address   decodeAddress   `json:"address"`
+	Addresses []decodeAddress `json:"addresses"`
+}
+
+func TestDecodeErrors(t *testing.T) {
+	decode := func(j JSON, body string) error {
+		var u decodeUser
+		return j.Decode(strings.NewReader(body), &u)
+	}
+
+	tests := []struct {
+		Name   string
+		Err    error
+		Errors Errors
+	}{
+		{"Type", decode(JSON{}, `{"age":"foo"}`), Errors{
+			StructError{Field: "age", Message: NewValidationError("decode.type", "expected {type}", map[string]interface{}{"type": "number", "value": "string", "offset": int64(12)})},
+		}},
+		{"NestedType", decode(JSON{}, `{"address":{"city":1}}`), Errors{
+			StructError{Field: "address", Message: Errors{
+				StructError{Field: "city", Message: NewValidationError("decode.type", "expected {type}", map[string]interface{}{"type": "string", "value": "number", "offset": int64(20)})},
+			}},
+		}},
+		{"Syntax", decode(JSON{}, `{"age":}`), Errors{
+			NewValidationError("decode.syntax", "malformed body", map[string]interface{}{"offset": int64(8)}),
+		}},
+		{"Empty", decode(JSON{}, ``), Errors{ErrDecodeEmpty}},
+		{"Truncated", decode(JSON{}, `{"age":1`), Errors{ErrDecodeTruncated}},
+		{"UnknownField", decode(JSON{DisallowUnknownFields: true}, `{"name":1}`), Errors{
+			StructError{Field: "name", Message: ErrDecodeUnknownField},
+		}},
+		{"DuplicateKey", decode(JSON{DisallowDuplicateKeys: true}, `{"addresses":[{"city":"a","city":"b"}]}`), Errors{
+			StructError{Field: "addresses", Message: Errors{
+				SliceError{Index: 0, Message: Errors{
+					StructError{Field: "city", Message: NewValidationError("decode.duplicate_key", "duplicate key", map[string]interface{}{"offset": int64(32)})},
+				}},
+			}},
+		}},
+		{"TrailingData", decode(JSON{DisallowTrailingData: true}, `{} {}`), Errors{
+			NewValidationError("decode.trailing_data", "unexpected data after body", map[string]interface{}{"offset": int64(2)}),
+		}},
+		{"XMLSyntax", xml.Unmarshal([]byte("<a>\n</b>"), new(struct{})), Errors{
+			NewValidationError("decode.syntax", "malformed body", map[string]interface{}{"line": 2}),
+		}},
+		{"Bind", &BindError{Key: "items.1.age", Value: "foo", Type: "number"}, Errors{
+			StructError{Field: "items", Message: Errors{
+				SliceError{Index: 1, Message: Errors{
+					StructError{Field: "age", Message: NewValidationError("decode.type", "expected {type}", map[string]interface{}{"type": "number", "value": "foo"})},
+				}},
+			}},
+		}},
+		{"Unknown", errors.New("foo"), nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Errors, decodeErrors(test.Err))
+		})
+	}
+}
+
+func TestDecodeError_Error(t *testing.T) {
+	assert.EqualError(t, newDecodeError(errors.New("foo")), "foo")
+	assert.EqualError(t, newDecodeError(io.EOF), "body is empty")
+
+	de := newDecodeError(&UnknownFieldError{Field: "name"})
+	assert.EqualError(t, de, "name: unknown field")
+
+	var ufe *UnknownFieldError
+	assert.True(t, errors.As(de, &ufe))
+
+	assert.Equal(t, []FieldError{{Path: Path{"name"}, Message: ErrDecodeUnknownField}}, Flatten(de))
+	assert.Equal(t, &DecodeError{
+		Err:    de.Err,
+		Errors: Errors{StructError{Field: "name", Message: NewValidationError("decode.unknown_field", "unbekanntes Feld", nil)}},
+	}, Translate(de, "de"))
+}
+
+func TestTypeName(t *testing.T) {
+	tests := []struct {
+		Value interface{}
+		Name  string
+	}{
+		{true, "boolean"},
+		{1, "number"},
+		{uint8(1), "number"},
+		{1.5, "number"},
+		{Positive(1), "number"},
+		{"foo", "string"},
+		{time.Time{}, "string"},
+		{[]int{}, "array"},
+		{[2]int{}, "array"},
+		{map[string]int{}, "object"},
+		{decodeUser{}, "object"},
+		{new(*int), "number"},
+		{make(chan int), "chan int"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.Name, typeName(reflect.TypeOf(test.Value)), "%T", test.Value)
+	}
+}
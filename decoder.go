@@ -1,9 +1,12 @@
 package legit
 
 import (
+	"bytes"
 	"encoding/json"
-	"encoding/xml"
 	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
 	"strings"
 )
 
@@ -16,13 +19,44 @@ type Decoder interface {
 	Decode(r io.Reader, dst interface{}) error
 }
 
+// TagNamer is implemented by decoders whose wire format names struct fields
+// with a struct tag, allowing errors to be reported using those names
+type TagNamer interface {
+	// return the struct tag, such as "json", used to name fields
+	TagName() string
+}
+
+// ParamsDecoder is implemented by decoders which require the parameters of the
+// MIME type they matched, such as the boundary of a multipart body. Form
+// prefers DecodeParams over Decode.
+type ParamsDecoder interface {
+	// return nil if data from reader was unmarshaled into dst
+	DecodeParams(r io.Reader, params map[string]string, dst interface{}) error
+}
+
+// RequestDecoder is implemented by decoders which decode a HTTP request
+// rather than its body, allowing net/http to manage resources such as the
+// temporary files of a multipart body. ParseRequestAndValidate prefers
+// DecodeRequest over Decode.
+type RequestDecoder interface {
+	// return nil if data from request was unmarshaled into dst
+	DecodeRequest(r *http.Request, dst interface{}) error
+}
+
 // Decoders contains multiple decoders for matching
 type Decoders []Decoder
 
-// return the first matching decoder for a MIME type, or nil if no match
+// return the first matching decoder for a MIME type, or nil if no match. The
+// MIME type is parsed, and decoders are given the media type in lower case
+// without its parameters.
 func (d Decoders) Match(mime string) Decoder {
+	mt, _, ok := parseMediaType(mime)
+	if !ok {
+		return nil
+	}
+
 	for _, dec := range d {
-		if dec.Match(mime) {
+		if dec.Match(mt) {
 			return dec
 		}
 	}
@@ -30,23 +64,201 @@ func (d Decoders) Match(mime string) Decoder {
 	return nil
 }
 
-// JSON decoder can decode any JSON body with the MIME type "application/json"
-type JSON struct{}
+// returns the lower case media type and parameters of a MIME type such as a
+// "Content-Type" header, and false if it is malformed. Malformed parameters
+// are discarded.
+func parseMediaType(s string) (string, map[string]string, bool) {
+	mt, params, err := mime.ParseMediaType(s)
+	if err == mime.ErrInvalidMediaParameter {
+		return mt, map[string]string{}, true
+	} else if err != nil {
+		return "", nil, false
+	}
+
+	return mt, params, true
+}
+
+// returns true if a media type is JSON, including structured syntax suffixes
+// such as "application/problem+json"
+func isJSON(mt string) bool {
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// returns true if a media type is XML, including structured syntax suffixes
+// such as "application/atom+xml"
+func isXML(mt string) bool {
+	return mt == "application/xml" || mt == "text/xml" || strings.HasSuffix(mt, "+xml")
+}
+
+// returns true if a MIME type has the given media type
+func isMediaType(mime, want string) bool {
+	mt, _, ok := parseMediaType(mime)
+	return ok && mt == want
+}
+
+// JSON decoder can decode any JSON body with the MIME type "application/json",
+// or a type with the "+json" suffix such as "application/problem+json". Bodies
+// are transcoded to UTF-8 from the charset parameter of the MIME type, or from
+// UTF-16 if they begin with its byte order mark.
+type JSON struct {
+	// DisallowUnknownFields returns an UnknownFieldError when an object
+	// contains a key which does not match any field of the destination struct
+	DisallowUnknownFields bool
+
+	// DisallowTrailingData returns a TrailingDataError when anything other
+	// than whitespace follows the first JSON value
+	DisallowTrailingData bool
+
+	// UseNumber decodes numbers into an interface{} as a json.Number rather
+	// than a float64
+	UseNumber bool
+
+	// DisallowDuplicateKeys returns a DuplicateKeyError when an object contains
+	// the same key more than once. Keys of objects decoded into a struct are
+	// compared ignoring case, as encoding/json does when matching keys to
+	// fields. The body is buffered in memory to check it
+	// before decoding.
+	DisallowDuplicateKeys bool
+
+	// Limits restricts the complexity of a body, which is buffered in memory
+	// to check it before decoding
+	Limits
+}
 
 func (j JSON) Match(mime string) bool {
-	return strings.HasPrefix(mime, "application/json")
+	mt, _, ok := parseMediaType(mime)
+	return ok && isJSON(mt)
 }
 
 func (j JSON) Decode(r io.Reader, dst interface{}) error {
-	return json.NewDecoder(r).Decode(dst)
+	return j.DecodeParams(r, nil, dst)
+}
+
+func (j JSON) DecodeParams(r io.Reader, params map[string]string, dst interface{}) error {
+	r, _, err := transcode(r, params["charset"])
+	if err != nil {
+		return err
+	}
+
+	if j.DisallowDuplicateKeys || j.Limits.enabled() {
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+
+		if err := j.scan(b, dst); err != nil {
+			return err
+		}
+
+		r = bytes.NewReader(b)
+	}
+
+	dec := json.NewDecoder(r)
+	if j.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if j.UseNumber {
+		dec.UseNumber()
+	}
+
+	if err := dec.Decode(dst); err != nil {
+		return unknownField(err)
+	}
+
+	if j.DisallowTrailingData {
+		offset := dec.InputOffset()
+		if _, err := dec.Token(); err != io.EOF {
+			return &TrailingDataError{Offset: offset}
+		}
+	}
+
+	return nil
+}
+
+func (j JSON) TagName() string {
+	return "json"
 }
 
-type XML struct{}
+// XML decoder can decode any XML body with the MIME type "application/xml" or
+// "text/xml", or a type with the "+xml" suffix such as "application/atom+xml".
+// Bodies are transcoded to UTF-8 from the charset parameter of the MIME type,
+// from UTF-16 if they begin with its byte order mark, or otherwise from the
+// encoding declared by their prolog.
+type XML struct {
+	// Limits restricts the complexity of a body, which is buffered in memory
+	// to check it before decoding
+	Limits
+
+	// MaxTokens limits the number of elements, attributes excluded, character
+	// data, comments and other tokens in a body. Zero disables the limit.
+	MaxTokens int
+
+	// MaxAttributes limits the number of attributes of an element. Zero
+	// disables the limit.
+	MaxAttributes int
+
+	// DisallowDOCTYPE returns ErrDOCTYPE when a body contains a DOCTYPE
+	// declaration
+	DisallowDOCTYPE bool
+
+	// Lenient disables strict parsing, accepting unquoted attributes, unknown
+	// entities and unclosed HTML elements, as xml.Decoder with Strict unset
+	Lenient bool
+
+	// DisallowUnknownElements returns an UnknownElementError when an element
+	// does not match any field of the destination struct
+	DisallowUnknownElements bool
+
+	// DisallowTrailingData returns a TrailingDataError when anything other
+	// than whitespace, comments or processing instructions follows the first
+	// document
+	DisallowTrailingData bool
+}
 
 func (x XML) Match(mime string) bool {
-	return strings.HasPrefix(mime, "application/xml")
+	mt, _, ok := parseMediaType(mime)
+	return ok && isXML(mt)
 }
 
 func (x XML) Decode(r io.Reader, dst interface{}) error {
-	return xml.NewDecoder(r).Decode(dst)
+	return x.DecodeParams(r, nil, dst)
+}
+
+func (x XML) DecodeParams(r io.Reader, params map[string]string, dst interface{}) error {
+	r, converted, err := transcode(r, params["charset"])
+	if err != nil {
+		return err
+	}
+
+	if x.scans() {
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+
+		if err := x.scan(b, converted, dst); err != nil {
+			return err
+		}
+
+		r = bytes.NewReader(b)
+	}
+
+	dec, cs := x.newDecoder(r, converted)
+	if err := dec.Decode(dst); err != nil {
+		// encoding/xml discards the type of errors opening a charset
+		if cs.err != nil {
+			return cs.err
+		}
+		return err
+	}
+
+	if x.DisallowTrailingData {
+		return trailingXML(dec)
+	}
+
+	return nil
+}
+
+func (x XML) TagName() string {
+	return "xml"
 }
@@ -3,6 +3,7 @@ package legit
 import (
 	"bytes"
 	"encoding/xml"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -11,14 +12,62 @@ import (
 func TestDecoders_Match(t *testing.T) {
 	d := Decoders{JSON{}}
 	assert.Equal(t, JSON{}, d.Match("application/json; charset=utf-8"))
+	assert.Equal(t, JSON{}, d.Match("Application/JSON"))
 	assert.Equal(t, nil, d.Match("application/xml"))
+	assert.Equal(t, nil, d.Match("application/json/foo"))
+	assert.Equal(t, nil, d.Match(""))
+
+	var mt string
+	d = Decoders{matchFunc(func(s string) bool { mt = s; return true })}
+	d.Match("Application/Vnd.API+JSON; charset=UTF-8")
+	assert.Equal(t, "application/vnd.api+json", mt)
+}
+
+type matchFunc func(string) bool
+
+func (fn matchFunc) Match(mime string) bool {
+	return fn(mime)
+}
+
+func (fn matchFunc) Decode(r io.Reader, dst interface{}) error {
+	return nil
+}
+
+func TestParseMediaType(t *testing.T) {
+	mt, params, ok := parseMediaType("Text/XML; Charset=ISO-8859-1")
+	assert.True(t, ok)
+	assert.Equal(t, "text/xml", mt)
+	assert.Equal(t, map[string]string{"charset": "ISO-8859-1"}, params)
+
+	mt, params, ok = parseMediaType("application/json; charset")
+	assert.True(t, ok)
+	assert.Equal(t, "application/json", mt)
+	assert.Empty(t, params)
+
+	_, _, ok = parseMediaType("application json")
+	assert.False(t, ok)
 }
 
 func TestJSON_Match(t *testing.T) {
-	j := JSON{}
-	assert.True(t, j.Match("application/json"))
-	assert.True(t, j.Match("application/json; charset=utf-8"))
-	assert.False(t, j.Match("application/xml"))
+	tests := []struct {
+		MIME  string
+		Match bool
+	}{
+		{"application/json", true},
+		{"application/json; charset=utf-8", true},
+		{"Application/JSON", true},
+		{"application/vnd.api+json", true},
+		{"application/problem+json", true},
+		{"application/ld+json; profile=\"https://www.w3.org/ns/activitystreams\"", true},
+		{"application/jsonfoo", false},
+		{"application/json-seq", false},
+		{"application/xml", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.Match, JSON{}.Match(test.MIME), test.MIME)
+	}
 }
 
 func TestJSON_Decode(t *testing.T) {
@@ -33,11 +82,23 @@ func TestJSON_Decode(t *testing.T) {
 }
 
 func TestXML_Match(t *testing.T) {
-	x := XML{}
+	tests := []struct {
+		MIME  string
+		Match bool
+	}{
+		{"application/xml", true},
+		{"application/xml; charset=utf-8", true},
+		{"APPLICATION/XML", true},
+		{"text/xml", true},
+		{"application/atom+xml", true},
+		{"application/problem+xml", true},
+		{"application/xmlfoo", false},
+		{"application/json", false},
+	}
 
-	assert.True(t, x.Match("application/xml"))
-	assert.True(t, x.Match("application/xml; charset=utf-8"))
-	assert.False(t, x.Match("application/json"))
+	for _, test := range tests {
+		assert.Equal(t, test.Match, XML{}.Match(test.MIME), test.MIME)
+	}
 }
 
 func TestXML_Decode(t *testing.T) {
@@ -54,3 +115,11 @@ func TestXML_Decode(t *testing.T) {
 		assert.Equal(t, "Hello World", body.Value)
 	}
 }
+
+func TestJSON_TagName(t *testing.T) {
+	assert.Equal(t, "json", JSON{}.TagName())
+}
+
+func TestXML_TagName(t *testing.T) {
+	assert.Equal(t, "xml", XML{}.TagName())
+}
@@ -0,0 +1,77 @@
+package legit
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// UnsupportedEncodingError is returned when a request body has a
+// "Content-Encoding" which cannot be decoded
+type UnsupportedEncodingError struct {
+	Encoding string
+}
+
+// returns the string representation of the unsupported encoding
+func (ue *UnsupportedEncodingError) Error() string {
+	return fmt.Sprintf("unsupported content encoding %q", ue.Encoding)
+}
+
+// decompress returns a body decoded from the content codings listed by a
+// "Content-Encoding" header, which are applied in the order listed. Closing
+// the returned body closes the original.
+func decompress(body io.ReadCloser, header string) (io.ReadCloser, error) {
+	codings := strings.Split(header, ",")
+
+	var r io.Reader = body
+	for i := len(codings) - 1; i >= 0; i-- {
+		coding := strings.ToLower(strings.TrimSpace(codings[i]))
+
+		var err error
+		switch coding {
+		case "", "identity":
+			continue
+		case "gzip", "x-gzip":
+			r, err = gzip.NewReader(r)
+		case "deflate":
+			r, err = newDeflateReader(r)
+		default:
+			return nil, &UnsupportedEncodingError{Encoding: strings.TrimSpace(codings[i])}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r == io.Reader(body) {
+		return body, nil
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{r, body}, nil
+}
+
+// returns a reader of a "deflate" coded body, which should be zlib wrapped
+// but is sent as raw deflate by some clients
+func newDeflateReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+
+	header, err := br.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+
+	// the compression method of a zlib header is 8 (deflate), and the header
+	// is a multiple of 31
+	if header[0]&0x0F == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+
+	return flate.NewReader(br), nil
+}
@@ -0,0 +1,107 @@
+package legit
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func compress(t *testing.T, coding, s string) []byte {
+	var buf bytes.Buffer
+
+	var w io.WriteCloser
+	switch coding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "zlib":
+		w = zlib.NewWriter(&buf)
+	case "flate":
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+	}
+
+	io.WriteString(w, s)
+	if !assert.NoError(t, w.Close()) {
+		t.FailNow()
+	}
+
+	return buf.Bytes()
+}
+
+func TestDecompress(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Header string
+		Body   []byte
+	}{
+		{"None", "", []byte("hello world")},
+		{"Identity", "identity", []byte("hello world")},
+		{"Gzip", "gzip", compress(t, "gzip", "hello world")},
+		{"XGzip", "X-GZIP", compress(t, "gzip", "hello world")},
+		{"Deflate", "deflate", compress(t, "zlib", "hello world")},
+		{"RawDeflate", "deflate", compress(t, "flate", "hello world")},
+		{"Multiple", "deflate, gzip", compress(t, "gzip", string(compress(t, "zlib", "hello world")))},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			body, err := decompress(ioutil.NopCloser(bytes.NewReader(test.Body)), test.Header)
+			if assert.NoError(t, err) {
+				b, err := ioutil.ReadAll(body)
+				assert.NoError(t, err)
+				assert.Equal(t, "hello world", string(b))
+				assert.NoError(t, body.Close())
+			}
+		})
+	}
+
+	_, err := decompress(ioutil.NopCloser(strings.NewReader("")), "gzip, br")
+	assert.Equal(t, &UnsupportedEncodingError{Encoding: "br"}, err)
+	assert.EqualError(t, err, `unsupported content encoding "br"`)
+
+	_, err = decompress(ioutil.NopCloser(strings.NewReader("hello world")), "gzip")
+	assert.Equal(t, gzip.ErrHeader, err)
+}
+
+func TestForm_ParseRequestAndValidate_contentEncoding(t *testing.T) {
+	newRequest := func(coding string, body []byte) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("Content-Encoding", coding)
+		return r
+	}
+
+	var u jsonUser
+	err := form.ParseRequestAndValidate(newRequest("gzip", compress(t, "gzip", `{"name":"foo"}`)), &u)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "foo", u.Name)
+	}
+
+	// a small compressed body is limited by its decompressed size
+	f := NewForm()
+	f.MaxBodyBytes = 4096
+	bomb := compress(t, "gzip", `{"name":"`+strings.Repeat("a", 1<<20)+`"}`)
+	assert.True(t, len(bomb) < 4096)
+
+	err = f.ParseRequestAndValidate(newRequest("gzip", bomb), &u)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, NewProblem(err).Status)
+
+	err = f.ParseRequestAndValidate(newRequest("gzip", []byte("hello world")), &u)
+	assert.Equal(t, http.StatusBadRequest, NewProblem(err).Status)
+
+	err = f.ParseRequestAndValidate(newRequest("br", []byte("{}")), &u)
+	assert.Equal(t, &UnsupportedEncodingError{Encoding: "br"}, err)
+
+	w := httptest.NewRecorder()
+	assert.NoError(t, WriteProblem(w, newRequest("br", nil), err))
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	assert.Equal(t, "gzip, deflate", w.Header().Get("Accept-Encoding"))
+}
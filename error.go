@@ -1,13 +1,21 @@
 package legit
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
+// ErrTruncated is appended to Errors when validation stopped after reaching
+// Legit.MaxErrors.
+var ErrTruncated = errors.New("too many errors")
+
 // Errors contains one or more validate errors
 type Errors []error
 
-// returns string representation of the first validation error encountered
+// returns string representation of the first validation error encountered,
+// Legit.SummarizeErrors returns a SummaryError summarizing every failure
 func (e Errors) Error() string {
 	if len(e) > 0 {
 		return e[0].Error()
@@ -16,6 +24,51 @@ func (e Errors) Error() string {
 	return ""
 }
 
+// returns the string representation of every leaf validation failure and its
+// path, separated by semicolons
+func (e Errors) Summary() string {
+	var msgs []string
+	for _, fe := range e.Flatten() {
+		msgs = append(msgs, fe.Error())
+	}
+
+	if e.Truncated() {
+		msgs = append(msgs, ErrTruncated.Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// returns the errors contained, allowing errors.Is and errors.As to inspect
+// every failure
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// returns true if validation stopped before all errors were collected
+func (e Errors) Truncated() bool {
+	return len(e) > 0 && e[len(e)-1] == ErrTruncated
+}
+
+// returns every leaf validation failure in the tree of errors, along with the
+// path to the value which failed
+func (e Errors) Flatten() []FieldError {
+	return flatten(nil, e, nil)
+}
+
+// SummaryError is returned in place of Errors when Legit.SummarizeErrors is
+// set, its string representation is the summary of every failure rather than
+// only the first
+type SummaryError struct {
+	Errors
+}
+
+// returns the string representation of every leaf validation failure and its
+// path, separated by semicolons
+func (se SummaryError) Error() string {
+	return se.Summary()
+}
+
 // StructError contains the field name and message of a failed validation
 type StructError struct {
 	Field   string `json:"field"`
@@ -27,6 +80,11 @@ func (se StructError) Error() string {
 	return fmt.Sprintf("%s: %s", se.Field, se.Message)
 }
 
+// returns the message of the failed validation
+func (se StructError) Unwrap() error {
+	return se.Message
+}
+
 // SliceError contains the index and message of a failed validation
 type SliceError struct {
 	Index   int   `json:"index"`
@@ -37,3 +95,200 @@ type SliceError struct {
 func (se SliceError) Error() string {
 	return fmt.Sprintf("%d: %s", se.Index, se.Message)
 }
+
+// returns the message of the failed validation
+func (se SliceError) Unwrap() error {
+	return se.Message
+}
+
+// MapError contains the key and message of a failed validation. OnKey is set
+// when the key itself, rather than its value, failed validation.
+type MapError struct {
+	Key     string `json:"key"`
+	OnKey   bool   `json:"on_key,omitempty"`
+	Message error  `json:"message"`
+}
+
+// returns the string representation of the key and failed validation
+func (me MapError) Error() string {
+	if me.OnKey {
+		return fmt.Sprintf("key %s: %s", me.Key, me.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", me.Key, me.Message)
+}
+
+// returns the message of the failed validation
+func (me MapError) Unwrap() error {
+	return me.Message
+}
+
+// DepthError is returned when a value is nested deeper than the maximum depth
+// allowed by Legit
+type DepthError struct {
+	MaxDepth int `json:"max_depth"`
+}
+
+// returns the string representation of the exceeded depth
+func (de DepthError) Error() string {
+	return fmt.Sprintf("maximum depth of %d exceeded", de.MaxDepth)
+}
+
+// Path is the location of a value within a validated object. Each element is
+// either a string, naming a struct field or map key, or an int slice index.
+type Path []interface{}
+
+// returns the dotted representation of a path, i.e. users[3].email
+func (p Path) String() string {
+	var sb strings.Builder
+
+	for _, elem := range p {
+		switch e := elem.(type) {
+		case int:
+			sb.WriteString("[" + strconv.Itoa(e) + "]")
+		default:
+			if sb.Len() > 0 {
+				sb.WriteByte('.')
+			}
+			sb.WriteString(fmt.Sprint(e))
+		}
+	}
+
+	return sb.String()
+}
+
+// returns the RFC 6901 JSON Pointer representation of a path, i.e.
+// /users/3/email
+func (p Path) Pointer() string {
+	var sb strings.Builder
+
+	for _, elem := range p {
+		sb.WriteByte('/')
+		sb.WriteString(pointerEscaper.Replace(fmt.Sprint(elem)))
+	}
+
+	return sb.String()
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// FieldError contains a single validation failure and the path to the value
+// which failed
+type FieldError struct {
+	Path    Path  `json:"path"`
+	Message error `json:"message"`
+}
+
+// returns the string representation of the path and failed validation
+func (fe FieldError) Error() string {
+	if len(fe.Path) < 1 {
+		return fe.Message.Error()
+	}
+
+	return fmt.Sprintf("%s: %s", fe.Path, fe.Message)
+}
+
+// returns the message of the failed validation
+func (fe FieldError) Unwrap() error {
+	return fe.Message
+}
+
+// Flatten returns every leaf validation failure within err, along with the
+// path to the value which failed.
+func Flatten(err error) []FieldError {
+	if err == nil {
+		return nil
+	}
+
+	return flatten(nil, err, nil)
+}
+
+func flatten(path Path, err error, dst []FieldError) []FieldError {
+	switch e := err.(type) {
+	case Errors:
+		for _, err := range e {
+			// truncation is not a failure of any value
+			if err != ErrTruncated {
+				dst = flatten(path, err, dst)
+			}
+		}
+		return dst
+	case SummaryError:
+		return flatten(path, e.Errors, dst)
+	case StructError:
+		return flatten(append(path, e.Field), e.Message, dst)
+	case SliceError:
+		return flatten(append(path, e.Index), e.Message, dst)
+	case MapError:
+		return flatten(append(path, e.Key), e.Message, dst)
+	case *DecodeError:
+		if len(e.Errors) > 0 {
+			return flatten(path, e.Errors, dst)
+		}
+	}
+
+	// copy path as its backing array is shared with sibling failures
+	return append(dst, FieldError{Path: append(Path(nil), path...), Message: err})
+}
+
+// ValidationError is a validation failure with a machine readable code, such
+// as "email.invalid", and parameters describing the failure. Placeholders in
+// the message of the form {name} are replaced with the parameter of that name.
+type ValidationError struct {
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+}
+
+// NewValidationError returns a ValidationError for use by custom validators
+func NewValidationError(code, message string, params map[string]interface{}) *ValidationError {
+	return &ValidationError{
+		Code:    code,
+		Message: message,
+		Params:  params,
+	}
+}
+
+// returns the message of the failed validation with parameters substituted
+func (ve *ValidationError) Error() string {
+	return substitute(ve.Message, ve.Params)
+}
+
+// returns true if target is a ValidationError with the same code, allowing
+// errors.Is to match a failure against the exported validator errors
+func (ve *ValidationError) Is(target error) bool {
+	t, ok := target.(*ValidationError)
+	return ok && t.Code == ve.Code
+}
+
+// replace placeholders of the form {name} in msg with their parameter
+func substitute(msg string, params map[string]interface{}) string {
+	if len(params) < 1 || !strings.Contains(msg, "{") {
+		return msg
+	}
+
+	var sb strings.Builder
+	for {
+		i := strings.Index(msg, "{")
+		if i < 0 {
+			break
+		}
+		j := strings.Index(msg[i:], "}")
+		if j < 0 {
+			break
+		}
+
+		v, ok := params[msg[i+1:i+j]]
+		if !ok {
+			// leave unknown placeholders intact
+			sb.WriteString(msg[:i+j+1])
+		} else {
+			sb.WriteString(msg[:i])
+			sb.WriteString(fmt.Sprint(v))
+		}
+		msg = msg[i+j+1:]
+	}
+	sb.WriteString(msg)
+
+	return sb.String()
+}
@@ -19,3 +19,138 @@ func TestStructError_Error(t *testing.T) {
 func TestSliceError_Error(t *testing.T) {
 	assert.Equal(t, "1: bar", SliceError{Index: 1, Message: errors.New("bar")}.Error())
 }
+
+func TestMapError_Error(t *testing.T) {
+	assert.Equal(t, "foo: bar", MapError{Key: "foo", Message: errors.New("bar")}.Error())
+	assert.Equal(t, "key foo: bar", MapError{Key: "foo", OnKey: true, Message: errors.New("bar")}.Error())
+}
+
+func TestDepthError_Error(t *testing.T) {
+	assert.Equal(t, "maximum depth of 10 exceeded", DepthError{MaxDepth: 10}.Error())
+}
+
+func TestErrors_Truncated(t *testing.T) {
+	assert.False(t, Errors{}.Truncated())
+	assert.False(t, Errors{errors.New("foo")}.Truncated())
+	assert.True(t, Errors{errors.New("foo"), ErrTruncated}.Truncated())
+}
+
+func TestErrors_Flatten(t *testing.T) {
+	err := Errors{
+		StructError{Field: "Users", Message: Errors{
+			SliceError{Index: 3, Message: Errors{
+				StructError{Field: "Email", Message: ErrEmail},
+				StructError{Field: "Tags", Message: Errors{
+					MapError{Key: "a/b", Message: ErrLower},
+				}},
+			}},
+		}},
+		StructError{Field: "Name", Message: ErrRequired},
+		ErrTruncated,
+	}
+
+	assert.Equal(t, []FieldError{
+		{Path: Path{"Users", 3, "Email"}, Message: ErrEmail},
+		{Path: Path{"Users", 3, "Tags", "a/b"}, Message: ErrLower},
+		{Path: Path{"Name"}, Message: ErrRequired},
+	}, err.Flatten())
+}
+
+func TestFlatten(t *testing.T) {
+	assert.Nil(t, Flatten(nil))
+	assert.Equal(t, []FieldError{{Path: nil, Message: ErrLower}}, Flatten(ErrLower))
+	assert.Equal(t, []FieldError{{Path: Path{0}, Message: ErrLower}}, Flatten(SliceError{Index: 0, Message: ErrLower}))
+}
+
+func TestPath_String(t *testing.T) {
+	assert.Equal(t, "", Path{}.String())
+	assert.Equal(t, "users[3].email", Path{"users", 3, "email"}.String())
+	assert.Equal(t, "[0][1].name", Path{0, 1, "name"}.String())
+}
+
+func TestPath_Pointer(t *testing.T) {
+	assert.Equal(t, "", Path{}.Pointer())
+	assert.Equal(t, "/users/3/email", Path{"users", 3, "email"}.Pointer())
+	assert.Equal(t, "/tags/a~1b~0c", Path{"tags", "a/b~c"}.Pointer())
+}
+
+func TestFieldError_Error(t *testing.T) {
+	assert.Equal(t, "users[3].email: bar", FieldError{Path: Path{"users", 3, "email"}, Message: errors.New("bar")}.Error())
+	assert.Equal(t, "bar", FieldError{Message: errors.New("bar")}.Error())
+}
+
+func TestErrors_Summary(t *testing.T) {
+	err := Errors{
+		StructError{Field: "users", Message: Errors{SliceError{Index: 3, Message: Errors{StructError{Field: "email", Message: ErrEmail}}}}},
+		StructError{Field: "name", Message: ErrRequired},
+		ErrTruncated,
+	}
+	assert.Equal(t, "users[3].email: invalid email; name: string is required; too many errors", err.Summary())
+	assert.Equal(t, "users: 3: email: invalid email", err.Error())
+}
+
+type customError struct {
+	Code int
+}
+
+func (ce customError) Error() string {
+	return "custom"
+}
+
+func TestErrors_Unwrap(t *testing.T) {
+	err := Validate(struct {
+		Users []struct {
+			Email Email
+			Name  Validator
+		}
+	}{[]struct {
+		Email Email
+		Name  Validator
+	}{{"foo@example.org", nil}, {"foo@example.org", validatorFunc(func() error { return customError{Code: 42} })}}})
+
+	assert.False(t, errors.Is(err, ErrEmail))
+
+	var ce customError
+	if assert.True(t, errors.As(err, &ce)) {
+		assert.Equal(t, 42, ce.Code)
+	}
+
+	err = Validate(struct {
+		Names map[string]Lower
+	}{map[string]Lower{"a": "FOO"}})
+	assert.True(t, errors.Is(err, ErrLower))
+
+	l := Legit{Strict: true}
+	err = l.Validate(struct{ Name string }{"foo"})
+	assert.True(t, errors.Is(err, ErrStrict))
+
+	assert.True(t, errors.Is(FieldError{Message: ErrUUID}, ErrUUID))
+}
+
+type validatorFunc func() error
+
+func (fn validatorFunc) Validate() error {
+	return fn()
+}
+
+func TestValidationError_Error(t *testing.T) {
+	assert.Equal(t, "invalid email", ErrEmail.Error())
+
+	ve := NewValidationError("number.range", "must be between {min} and {max}, not {value", map[string]interface{}{"min": 1, "max": 10})
+	assert.Equal(t, "must be between 1 and 10, not {value", ve.Error())
+
+	ve = NewValidationError("foo", "{unknown} {a}{a}", map[string]interface{}{"a": "b"})
+	assert.Equal(t, "{unknown} bb", ve.Error())
+}
+
+func TestValidationError_Is(t *testing.T) {
+	ve := NewValidationError("email.invalid", "email is invalid", map[string]interface{}{"value": "foo"})
+	assert.True(t, errors.Is(ve, ErrEmail))
+	assert.False(t, errors.Is(ve, ErrUUID))
+	assert.False(t, errors.Is(ve, errors.New("email.invalid")))
+
+	var target *ValidationError
+	if assert.True(t, errors.As(StructError{Field: "email", Message: ErrEmail}, &target)) {
+		assert.Equal(t, "email.invalid", target.Code)
+	}
+}
@@ -0,0 +1,135 @@
+package legit
+
+import (
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// File is an uploaded file decoded from a multipart/form-data body. Custom
+// types embedding File may be used to validate uploads with FileLimits.
+type File struct {
+	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
+
+	// ContentType is detected from the content of the file, rather than
+	// trusting the type given by the client
+	ContentType string `json:"content_type"`
+
+	Header *multipart.FileHeader `json:"-"`
+}
+
+// newFile returns the File of an uploaded part, detecting its content type
+func newFile(fh *multipart.FileHeader) (File, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return File{}, err
+	}
+	defer f.Close()
+
+	// http.DetectContentType considers at most the first 512 bytes
+	buf := make([]byte, 512)
+	n, err := f.Read(buf)
+	if err != nil && n < 1 && fh.Size > 0 {
+		return File{}, err
+	}
+
+	return File{
+		Filename:    fh.Filename,
+		Size:        fh.Size,
+		ContentType: http.DetectContentType(buf[:n]),
+		Header:      fh,
+	}, nil
+}
+
+// Open returns the content of the uploaded file
+func (f File) Open() (multipart.File, error) {
+	return f.Header.Open()
+}
+
+// Validate always succeeds, uploads are validated with FileLimits. As a
+// Validator, the fields of a File are not visited by Legit, including in
+// strict mode.
+func (f File) Validate() error {
+	return nil
+}
+
+var (
+	// ErrFileTooLarge is returned when a File is larger than the maximum size
+	// of FileLimits.
+	ErrFileTooLarge = NewValidationError("file.too_large", "file is larger than {max} bytes", nil)
+
+	// ErrFileType is returned when the content type of a File is not allowed
+	// by FileLimits.
+	ErrFileType = NewValidationError("file.type_not_allowed", "file type {type} is not allowed", nil)
+
+	// ErrFileExtension is returned when the extension of a File is not
+	// allowed by FileLimits.
+	ErrFileExtension = NewValidationError("file.extension_not_allowed", "file extension {extension} is not allowed", nil)
+)
+
+// FileLimits validates the size, content type and extension of uploaded
+// files. Empty limits are not enforced.
+type FileLimits struct {
+	// MaxSize is the maximum size of a file in bytes
+	MaxSize int64
+
+	// Types are the allowed content types, detected from the content of a
+	// file, such as "image/png". Wildcards such as "image/*" are supported.
+	Types []string
+
+	// Extensions are the allowed file name extensions, such as ".png",
+	// compared case-insensitively
+	Extensions []string
+}
+
+// Validate returns a ValidationError if a file does not satisfy the limits
+func (fl FileLimits) Validate(f File) error {
+	if fl.MaxSize > 0 && f.Size > fl.MaxSize {
+		return NewValidationError(ErrFileTooLarge.Code, ErrFileTooLarge.Message, map[string]interface{}{"max": fl.MaxSize, "size": f.Size})
+	}
+
+	if len(fl.Types) > 0 {
+		// discard parameters such as charset
+		typ := strings.TrimSpace(strings.SplitN(f.ContentType, ";", 2)[0])
+		if !matchType(fl.Types, typ) {
+			return NewValidationError(ErrFileType.Code, ErrFileType.Message, map[string]interface{}{"type": typ})
+		}
+	}
+
+	if len(fl.Extensions) > 0 {
+		ext := strings.ToLower(filepath.Ext(f.Filename))
+		if !matchExtension(fl.Extensions, ext) {
+			return NewValidationError(ErrFileExtension.Code, ErrFileExtension.Message, map[string]interface{}{"extension": ext})
+		}
+	}
+
+	return nil
+}
+
+func matchType(types []string, typ string) bool {
+	for _, t := range types {
+		if strings.EqualFold(t, typ) {
+			return true
+		} else if strings.HasSuffix(t, "/*") && strings.HasPrefix(strings.ToLower(typ), strings.ToLower(t[:len(t)-1])) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchExtension(exts []string, ext string) bool {
+	for _, e := range exts {
+		if !strings.HasPrefix(e, ".") {
+			e = "." + e
+		}
+
+		if strings.EqualFold(e, ext) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,60 @@
+package legit
+
+import (
+	"mime/multipart"
+	"net/textproto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileLimits_Validate(t *testing.T) {
+	fl := FileLimits{
+		MaxSize:    1024,
+		Types:      []string{"image/*", "application/pdf"},
+		Extensions: []string{".png", "pdf"},
+	}
+
+	tests := []struct {
+		Name string
+		File File
+		Err  error
+	}{
+		{"Valid", File{Filename: "a.PNG", Size: 10, ContentType: "image/png"}, nil},
+		{"ValidParams", File{Filename: "a.pdf", Size: 1024, ContentType: "application/pdf"}, nil},
+		{"TooLarge", File{Filename: "a.png", Size: 1025, ContentType: "image/png"}, NewValidationError("file.too_large", "file is larger than {max} bytes", map[string]interface{}{"max": int64(1024), "size": int64(1025)})},
+		{"Type", File{Filename: "a.png", Size: 10, ContentType: "text/plain; charset=utf-8"}, NewValidationError("file.type_not_allowed", "file type {type} is not allowed", map[string]interface{}{"type": "text/plain"})},
+		{"Extension", File{Filename: "a.gif", Size: 10, ContentType: "image/gif"}, NewValidationError("file.extension_not_allowed", "file extension {extension} is not allowed", map[string]interface{}{"extension": ".gif"})},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := fl.Validate(test.File)
+			if test.Err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, test.Err, err)
+			}
+		})
+	}
+
+	assert.NoError(t, FileLimits{}.Validate(File{Filename: "a", Size: 1 << 40}))
+	assert.EqualError(t, fl.Validate(File{Size: 2048}), "file is larger than 1024 bytes")
+	assert.ErrorIs(t, fl.Validate(File{Size: 2048}), ErrFileTooLarge)
+}
+
+func TestFile_Validate(t *testing.T) {
+	var u struct {
+		Name   Alpha  `form:"name"`
+		Resume File   `form:"resume"`
+		Photos []File `form:"photos"`
+		Avatar *File  `form:"avatar"`
+	}
+	u.Name = "foo"
+	u.Resume = File{Filename: "cv.txt", Header: &multipart.FileHeader{Header: textproto.MIMEHeader{"Content-Type": {"text/plain"}}}}
+	u.Photos = []File{{Filename: "1.png"}}
+	u.Avatar = &File{Filename: "a.png"}
+
+	assert.NoError(t, Legit{Strict: true}.Validate(&u))
+	assert.NoError(t, Validate(&u))
+}
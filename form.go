@@ -1,8 +1,10 @@
 package legit
 
 import (
+	"context"
 	"errors"
 	"io"
+	"io/ioutil"
 	"net/http"
 )
 
@@ -12,21 +14,82 @@ var (
 	ErrEncoding = errors.New("unknown encoding")
 )
 
+// DecodeError is returned when a decoder fails to decode a request body,
+// distinguishing malformed input from input which failed validation
+type DecodeError struct {
+	Err error
+
+	// Errors locates the failure within the decoded value, in the same
+	// structure as validation failures, when the decoder reports where it
+	// occurred
+	Errors Errors
+}
+
+// newDecodeError returns the DecodeError of an error returned by a decoder
+func newDecodeError(err error) *DecodeError {
+	err = bodyTooLarge(err)
+	return &DecodeError{Err: err, Errors: decodeErrors(err)}
+}
+
+// returns the string representation of the decoding failure
+func (de *DecodeError) Error() string {
+	if len(de.Errors) > 0 {
+		return de.Errors.Error()
+	}
+
+	return de.Err.Error()
+}
+
+// returns the error returned by the decoder
+func (de *DecodeError) Unwrap() error {
+	return de.Err
+}
+
 // Form implements the decoding and validation of user data from readers
 // and HTTP requests
 type Form struct {
 	Legit    Legit
 	Decoders Decoders
+
+	// Translator rewrites validation errors into the language preferred by
+	// the "Accept-Language" header of a request, translation is disabled when
+	// nil
+	Translator *Translator
+
+	// MaxMemory is the memory used to store a multipart body before file parts
+	// are spilled to temporary files, overriding Multipart.MaxMemory when set.
+	// Multipart bodies decoded from a reader may not be larger.
+	MaxMemory int64
+
+	// MaxBodyBytes limits the size of a body, returning a LimitError once it
+	// is exceeded. Zero disables the limit.
+	MaxBodyBytes int64
+
+	// Limits restricts the complexity of JSON and XML bodies, overriding the
+	// limits of those decoders when set
+	Limits
+
+	// DefaultContentType is the MIME type of bodies without one, such as
+	// requests without a "Content-Type" header. When neither it nor
+	// SniffContentType is set, such bodies are rejected with ErrEncoding.
+	DefaultContentType string
+
+	// SniffContentType detects the MIME type of bodies without one from their
+	// first bytes, distinguishing JSON, XML and URL encoded form data. Bodies
+	// which are not recognised, or have no matching decoder, use
+	// DefaultContentType.
+	SniffContentType bool
 }
 
 var form = NewForm()
 
-// NewForm returns a Form assignment with the default Legit configuration and
-// a JSON decoder
+// NewForm returns a Form assignment with the default Legit configuration, a
+// JSON decoder and the default Translator
 func NewForm() Form {
 	return Form{
-		Legit:    New(),
-		Decoders: Decoders{JSON{}},
+		Legit:      New(),
+		Decoders:   Decoders{JSON{}},
+		Translator: translator,
 	}
 }
 
@@ -37,24 +100,111 @@ func ParseAndValidate(r io.Reader, mime string, dst interface{}) error {
 }
 
 // ParseAndValidate first decodes a reader using the first decoder matching the
-// given mime type, then applies validation to the collected input
+// given mime type, then applies validation to the collected input. Fields are
+// named in errors using the decoder's struct tag if it implements TagNamer
+// and Legit.TagName is not set.
 func (f Form) ParseAndValidate(r io.Reader, mime string, dst interface{}) error {
-	dec := f.Decoders.Match(mime)
+	return f.ParseAndValidateContext(context.Background(), r, mime, dst)
+}
+
+// ParseAndValidateContext is the same as ParseAndValidate, passing ctx on to
+// any ValidatorContext encountered during validation
+func ParseAndValidateContext(ctx context.Context, r io.Reader, mime string, dst interface{}) error {
+	return form.ParseAndValidateContext(ctx, r, mime, dst)
+}
+
+// ParseAndValidateContext is the same as ParseAndValidate, passing ctx on to
+// any ValidatorContext encountered during validation. Failures to decode the
+// reader are returned as a DecodeError, and failures of validation as Errors
+// or a SummaryError.
+func (f Form) ParseAndValidateContext(ctx context.Context, r io.Reader, mime string, dst interface{}) error {
+	r, mime = f.contentType(r, mime)
+
+	dec := f.decoder(mime)
 	if dec == nil {
 		return ErrEncoding
 	}
 
-	err := dec.Decode(r, dst)
+	if f.MaxBodyBytes > 0 {
+		r = http.MaxBytesReader(nil, ioutil.NopCloser(r), f.MaxBodyBytes)
+	}
+
+	var err error
+	if pd, ok := dec.(ParamsDecoder); ok {
+		_, params, _ := parseMediaType(mime)
+		err = pd.DecodeParams(r, params, dst)
+	} else {
+		err = dec.Decode(r, dst)
+	}
 	if err != nil {
+		return newDecodeError(err)
+	}
+
+	return f.validate(ctx, dec, dst)
+}
+
+// returns the MIME type of a body, sniffing it or using DefaultContentType if
+// not given, along with a reader replaying any bytes sniffed
+func (f Form) contentType(r io.Reader, mime string) (io.Reader, string) {
+	if mime != "" {
+		return r, mime
+	}
+
+	if f.SniffContentType {
+		var sniffed string
+		r, sniffed = sniffContentType(r)
+		if sniffed != "" && f.Decoders.Match(sniffed) != nil {
+			return r, sniffed
+		}
+	}
+
+	return r, f.DefaultContentType
+}
+
+// returns the first decoder matching a MIME type, configured by the Form
+func (f Form) decoder(mime string) Decoder {
+	switch d := f.Decoders.Match(mime).(type) {
+	case Multipart:
+		if f.MaxMemory > 0 {
+			d.MaxMemory = f.MaxMemory
+		}
+		return d
+	case JSON:
+		d.Limits = d.Limits.merge(f.Limits)
+		return d
+	case XML:
+		d.Limits = d.Limits.merge(f.Limits)
+		return d
+	case nil:
+		return nil
+	default:
+		return d
+	}
+}
+
+// validate input decoded by a decoder, reporting errors using the field names
+// of its wire format unless configured otherwise
+func (f Form) validate(ctx context.Context, dec Decoder, dst interface{}) error {
+	l := f.Legit
+	if tn, ok := dec.(TagNamer); ok && l.TagName == "" {
+		l.TagName = tn.TagName()
+	}
+
+	err := l.ValidateContext(ctx, dst)
+
+	// validators may return any error, so failures are always reported as
+	// Errors or a SummaryError to tell them apart from others such as a
+	// cancelled context
+	switch err.(type) {
+	case nil, Errors, SummaryError:
 		return err
 	}
 
-	err = f.Legit.Validate(dst)
-	if err != nil {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 		return err
 	}
 
-	return nil
+	return Errors{err}
 }
 
 // ParseRequestAndValidate is the same as ParseAndValidate accepting a HTTP
@@ -64,7 +214,90 @@ func ParseRequestAndValidate(r *http.Request, dst interface{}) error {
 }
 
 // ParseRequestAndValidate is the same as ParseAndValidate accepting a HTTP
-// request for the reader and using the "Content-Type" header for the MIME type
+// request for the reader and using the "Content-Type" header for the MIME type.
+// Bodies compressed with gzip or deflate are decompressed according to the
+// "Content-Encoding" header, MaxBodyBytes limiting the decompressed size.
+// Validation errors are translated into the language preferred by the
+// "Accept-Language" header.
 func (f Form) ParseRequestAndValidate(r *http.Request, dst interface{}) error {
-	return f.ParseAndValidate(r.Body, r.Header.Get("Content-Type"), dst)
+	err := f.parseRequest(r, dst)
+	return f.translate(r, err)
+}
+
+func (f Form) parseRequest(r *http.Request, dst interface{}) error {
+	// the body is decompressed before any limit is applied, so the size of the
+	// decompressed body is limited
+	body, err := decompress(r.Body, r.Header.Get("Content-Encoding"))
+	if err != nil {
+		var ue *UnsupportedEncodingError
+		if errors.As(err, &ue) {
+			return err
+		}
+		return newDecodeError(err)
+	}
+
+	r.Body = body
+
+	// a sniffed body is replayed from the returned reader
+	rd, ct := f.contentType(body, r.Header.Get("Content-Type"))
+	if rd != io.Reader(body) {
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{rd, body}
+	}
+
+	dec := f.decoder(ct)
+	if dec == nil {
+		return ErrEncoding
+	}
+
+	reqDec, ok := dec.(RequestDecoder)
+	if !ok {
+		return f.ParseAndValidateContext(r.Context(), r.Body, ct, dst)
+	}
+
+	if f.MaxBodyBytes > 0 {
+		r.Body = http.MaxBytesReader(nil, r.Body, f.MaxBodyBytes)
+	}
+
+	err = reqDec.DecodeRequest(r, dst)
+	if err != nil {
+		return newDecodeError(err)
+	}
+
+	return f.validate(r.Context(), dec, dst)
+}
+
+// ParseQueryAndValidate decodes the query string of a HTTP request in the same
+// way as the URLEncoded decoder, then applies validation to the collected
+// input. Validation errors are translated into the language preferred by the
+// "Accept-Language" header.
+func ParseQueryAndValidate(r *http.Request, dst interface{}) error {
+	return form.ParseQueryAndValidate(r, dst)
+}
+
+// ParseQueryAndValidate decodes the query string of a HTTP request in the same
+// way as the URLEncoded decoder, then applies validation to the collected
+// input. Validation errors are translated into the language preferred by the
+// "Accept-Language" header.
+func (f Form) ParseQueryAndValidate(r *http.Request, dst interface{}) error {
+	err := bindValues(r.URL.Query(), dst)
+	if err != nil {
+		return newDecodeError(err)
+	}
+
+	err = f.validate(r.Context(), URLEncoded{}, dst)
+	return f.translate(r, err)
+}
+
+// translate validation errors into the language preferred by a request
+func (f Form) translate(r *http.Request, err error) error {
+	if err != nil && f.Translator != nil {
+		if lang := f.Translator.Negotiate(r.Header.Get("Accept-Language")); lang != "" {
+			return f.Translator.Translate(err, lang)
+		}
+	}
+
+	return err
 }
@@ -2,8 +2,14 @@ package legit
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,6 +19,7 @@ func TestForm_NewForm(t *testing.T) {
 	f := NewForm()
 	assert.False(t, f.Legit.Strict)
 	assert.Equal(t, Decoders{JSON{}}, f.Decoders)
+	assert.True(t, translator == f.Translator)
 }
 
 func TestForm_ParseAndValidate(t *testing.T) {
@@ -34,7 +41,12 @@ func TestForm_ParseAndValidate_invalidBody(t *testing.T) {
 
 	var body string
 	err := form.ParseAndValidate(r, "application/json", &body)
-	assert.NotNil(t, err)
+	if assert.NotNil(t, err) {
+		assert.IsType(t, &DecodeError{}, err)
+
+		var se *json.SyntaxError
+		assert.True(t, errors.As(err, &se))
+	}
 }
 
 func TestForm_ParseAndValidate_validation(t *testing.T) {
@@ -43,7 +55,18 @@ func TestForm_ParseAndValidate_validation(t *testing.T) {
 	var body Lower
 	err := form.ParseAndValidate(r, "application/json", &body)
 	if assert.NotNil(t, err) {
-		assert.Equal(t, errLower, err)
+		assert.Equal(t, Errors{ErrLower}, err)
+	}
+}
+
+func TestForm_ParseAndValidateContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), tenantKey{}, "foo")
+	r := bytes.NewReader([]byte(`"bar"`))
+
+	var body tenantName
+	err := form.ParseAndValidateContext(ctx, r, "application/json", &body)
+	if assert.NotNil(t, err) {
+		assert.EqualError(t, err, "wrong tenant")
 	}
 }
 
@@ -58,3 +81,99 @@ func TestForm_ParseRequestAndValidate(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, Lower("foo"), body)
 }
+
+func TestForm_ParseRequestAndValidate_context(t *testing.T) {
+	r := &http.Request{
+		Body:   ioutil.NopCloser(bytes.NewReader([]byte(`"foo"`))),
+		Header: http.Header{"Content-Type": []string{"application/json"}},
+	}
+	r = r.WithContext(context.WithValue(context.Background(), tenantKey{}, "foo"))
+
+	var body tenantName
+	err := form.ParseRequestAndValidate(r, &body)
+	assert.NoError(t, err)
+}
+
+func TestForm_ParseAndValidate_tagName(t *testing.T) {
+	r := bytes.NewReader([]byte(`{"email": "foo"}`))
+
+	var body struct {
+		Email Email `json:"email"`
+	}
+	err := form.ParseAndValidate(r, "application/json", &body)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{StructError{Field: "email", Message: ErrEmail}}, err)
+	}
+
+	f := Form{Legit: Legit{TagName: "form"}, Decoders: Decoders{XML{}}}
+	r = bytes.NewReader([]byte(`<Body><email>foo</email></Body>`))
+
+	var xmlBody struct {
+		Email Email `xml:"email" form:"email_address"`
+	}
+	err = f.ParseAndValidate(r, "application/xml", &xmlBody)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{StructError{Field: "email_address", Message: ErrEmail}}, err)
+	}
+}
+
+func TestForm_ParseQueryAndValidate(t *testing.T) {
+	r := &http.Request{
+		URL:    &url.URL{RawQuery: "id=625e63f3-58f5-40b7-83a1-a72ad31acffb&email=foo@example.org&tags=foo&tags=BAR&address[city]=London"},
+		Header: http.Header{},
+	}
+
+	var body formUser
+	err := form.ParseQueryAndValidate(r, &body)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{StructError{Field: "tags", Message: Errors{SliceError{Index: 1, Message: ErrLower}}}}, err)
+		assert.Equal(t, Email("foo@example.org"), body.Email)
+		assert.Equal(t, Alpha("London"), body.Address.City)
+	}
+
+	r.URL.RawQuery = "age=foo"
+	err = ParseQueryAndValidate(r, &body)
+	if assert.NotNil(t, err) {
+		assert.IsType(t, &DecodeError{}, err)
+	}
+}
+
+func TestForm_ParseAndValidate_urlencoded(t *testing.T) {
+	f := Form{Legit: New(), Decoders: Decoders{JSON{}, URLEncoded{}}}
+	r := bytes.NewReader([]byte(`address[city]=L0ndon`))
+
+	var body struct {
+		Address formAddress `form:"address"`
+	}
+	err := f.ParseAndValidate(r, "application/x-www-form-urlencoded", &body)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{StructError{Field: "address", Message: Errors{StructError{Field: "city", Message: ErrAlpha}}}}, err)
+	}
+}
+
+type paramsDecoder struct {
+	params map[string]string
+}
+
+func (pd *paramsDecoder) Match(mime string) bool {
+	return mime == "text/plain"
+}
+
+func (pd *paramsDecoder) Decode(r io.Reader, dst interface{}) error {
+	return nil
+}
+
+func (pd *paramsDecoder) DecodeParams(r io.Reader, params map[string]string, dst interface{}) error {
+	pd.params = params
+	return nil
+}
+
+func TestForm_ParseAndValidate_params(t *testing.T) {
+	pd := &paramsDecoder{}
+	f := NewForm()
+	f.Decoders = Decoders{pd}
+
+	var body string
+	assert.NoError(t, f.ParseAndValidate(strings.NewReader(""), "Text/Plain; Charset=ISO-8859-1; format=flowed", &body))
+	assert.Equal(t, map[string]string{"charset": "ISO-8859-1", "format": "flowed"}, pd.params)
+}
@@ -0,0 +1,57 @@
+package legit
+
+import (
+	"net/http"
+)
+
+// Handler is a http.Handler which decodes and validates the body of each
+// request, or the query string of GET and HEAD requests, into a T before
+// calling Func, which is only ever called with a valid T. Requests which fail
+// are answered by WriteError.
+type Handler[T any] struct {
+	Form Form
+
+	// WriteError writes the response to a request which could not be decoded
+	// or validated, WriteProblem is used when nil
+	WriteError func(w http.ResponseWriter, r *http.Request, err error)
+
+	Func func(w http.ResponseWriter, r *http.Request, v T)
+}
+
+// Handle returns a Handler using the default Form configuration and writing
+// RFC 7807 problem responses for requests which fail
+func Handle[T any](f func(w http.ResponseWriter, r *http.Request, v T)) Handler[T] {
+	return Handler[T]{
+		Form:       form,
+		WriteError: writeProblem,
+		Func:       f,
+	}
+}
+
+func (h Handler[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var v T
+
+	var err error
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		err = h.Form.ParseQueryAndValidate(r, &v)
+	} else {
+		err = h.Form.ParseRequestAndValidate(r, &v)
+	}
+	if err != nil {
+		writeError := h.WriteError
+		if writeError == nil {
+			writeError = writeProblem
+		}
+
+		writeError(w, r, err)
+		return
+	}
+
+	h.Func(w, r, v)
+}
+
+// WriteProblem without an error result, as there is no further response to
+// write if it fails
+func writeProblem(w http.ResponseWriter, r *http.Request, err error) {
+	WriteProblem(w, r, err)
+}
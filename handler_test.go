@@ -0,0 +1,125 @@
+package legit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type handlerUser struct {
+	Email Email    `json:"email"`
+	Age   Positive `json:"age"`
+}
+
+func TestHandle(t *testing.T) {
+	var called int
+	h := Handle(func(w http.ResponseWriter, r *http.Request, u handlerUser) {
+		called++
+		assert.Equal(t, handlerUser{Email: "foo@example.org", Age: 30}, u)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	r := httptest.NewRequest("POST", "/", strings.NewReader(`{"email": "foo@example.org", "age": 30}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, 1, called)
+
+	r = httptest.NewRequest("POST", "/", strings.NewReader(`{"email": "foo", "age": -1}`))
+	r.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"pointer":"/age"`)
+	assert.Equal(t, 1, called)
+
+	r = httptest.NewRequest("POST", "/", strings.NewReader(`<user/>`))
+	r.Header.Set("Content-Type", "application/xml")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	assert.Equal(t, 1, called)
+}
+
+type handlerSignup struct {
+	Name string `json:"name"`
+}
+
+func (s handlerSignup) Validate() error {
+	if s.Name == "" {
+		return errors.New("name required")
+	}
+
+	return nil
+}
+
+func TestHandle_validator(t *testing.T) {
+	h := Handle(func(w http.ResponseWriter, r *http.Request, s handlerSignup) {
+		t.Fatal("called with an invalid body")
+	})
+
+	// errors returned by a validator of the body itself are still failures of
+	// validation, rather than internal errors
+	r := httptest.NewRequest("POST", "/", strings.NewReader(`{}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"message":"name required"`)
+}
+
+func TestHandle_query(t *testing.T) {
+	var called bool
+	h := Handle(func(w http.ResponseWriter, r *http.Request, u handlerUser) {
+		called = true
+		assert.Equal(t, handlerUser{Email: "foo@example.org", Age: 30}, u)
+	})
+
+	r := httptest.NewRequest("GET", "/?Email=foo@example.org&Age=30", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.True(t, called)
+
+	called = false
+	r = httptest.NewRequest("GET", "/?Email=foo", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.False(t, called)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"pointer":"/Email"`)
+}
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	var called bool
+	h := Handler[*handlerUser]{
+		Form: Form{Legit: New(), Decoders: Decoders{XML{}}},
+		Func: func(w http.ResponseWriter, r *http.Request, u *handlerUser) {
+			called = true
+			assert.Equal(t, &handlerUser{Email: "foo@example.org"}, u)
+		},
+	}
+
+	r := httptest.NewRequest("POST", "/", strings.NewReader(`<user><Email>foo@example.org</Email></user>`))
+	r.Header.Set("Content-Type", "application/xml")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.True(t, called)
+
+	// custom error writers replace problem responses
+	h.WriteError = func(w http.ResponseWriter, r *http.Request, err error) {
+		http.Error(w, err.Error(), http.StatusTeapot)
+	}
+
+	r = httptest.NewRequest("POST", "/", strings.NewReader(`{}`))
+	r.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusTeapot, w.Code)
+	assert.Equal(t, "unknown encoding\n", w.Body.String())
+}
@@ -0,0 +1,307 @@
+package legit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// UnknownFieldError is returned by the JSON decoder when DisallowUnknownFields
+// is set and an object contains a key not matching any field
+type UnknownFieldError struct {
+	Field string
+}
+
+// returns the string representation of the unknown field
+func (ue *UnknownFieldError) Error() string {
+	return fmt.Sprintf("json: unknown field %q", ue.Field)
+}
+
+// DuplicateKeyError is returned by the JSON decoder when DisallowDuplicateKeys
+// is set and an object contains the same key more than once. Keys of objects
+// decoded into a struct are compared ignoring case, as encoding/json does when
+// matching keys to fields. Path is the
+// location of the duplicated key, ending with the key itself.
+type DuplicateKeyError struct {
+	Key    string
+	Path   Path
+	Offset int64
+}
+
+// returns the string representation of the duplicated key
+func (de *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("json: duplicate key %q at offset %d", de.Path, de.Offset)
+}
+
+// TrailingDataError is returned by the JSON and XML decoders when
+// DisallowTrailingData is set and data follows the first value or document.
+// Offset is the end of the first value.
+type TrailingDataError struct {
+	Offset int64
+}
+
+// returns the string representation of the trailing data
+func (te *TrailingDataError) Error() string {
+	return fmt.Sprintf("unexpected data after offset %d", te.Offset)
+}
+
+// encoding/json only reports unknown fields as an unstructured error of the
+// form `json: unknown field "name"`
+const unknownFieldPrefix = "json: unknown field "
+
+// returns an UnknownFieldError in place of the error returned by encoding/json
+func unknownField(err error) error {
+	msg := err.Error()
+	if !strings.HasPrefix(msg, unknownFieldPrefix) {
+		return err
+	}
+
+	field, uerr := strconv.Unquote(msg[len(unknownFieldPrefix):])
+	if uerr != nil {
+		return err
+	}
+
+	return &UnknownFieldError{Field: field}
+}
+
+// jsonFrame is an object or array currently being scanned
+type jsonFrame struct {
+	object bool
+
+	// the type decoded from the object or array, nil if unknown, and the type
+	// decoded from its current element
+	typ  reflect.Type
+	elem reflect.Type
+
+	// keys seen in an object, folded if it is decoded into a struct, and
+	// whether the next token is a key
+	keys      map[string]struct{}
+	expectKey bool
+
+	// the key or index of the current element
+	key   string
+	index int
+}
+
+// scan returns a DuplicateKeyError if DisallowDuplicateKeys is set and any
+// object within b contains the same key more than once, or a LimitError if b
+// exceeds the limits of the decoder. Malformed JSON is ignored, and reported
+// when decoding.
+func (j JSON) scan(b []byte, dst interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var stack []*jsonFrame
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+
+		var top *jsonFrame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		isKey := top != nil && top.object && top.expectKey
+
+		if s, ok := tok.(string); ok && j.MaxStringLength > 0 && utf8.RuneCountInString(s) > j.MaxStringLength {
+			// keys are reported at the object containing them
+			path := jsonPath(stack)
+			if isKey {
+				path = jsonPath(stack[:len(stack)-1])
+			}
+
+			return &LimitError{Err: ErrStringTooLong, Max: int64(j.MaxStringLength), Path: path, Offset: dec.InputOffset()}
+		}
+
+		if isKey {
+			// a closing delimiter in place of a key ends the object
+			if key, ok := tok.(string); ok {
+				// encoding/json matches keys to fields case-insensitively, so
+				// keys differing only in case would overwrite the same field,
+				// whereas map keys are distinct
+				seenKey := key
+				top.elem = nil
+				if top.typ != nil {
+					switch top.typ.Kind() {
+					case reflect.Struct:
+						seenKey = foldName(key)
+						top.elem = jsonFieldsOf(top.typ)[seenKey]
+					case reflect.Map:
+						top.elem = top.typ.Elem()
+					}
+				}
+
+				if _, seen := top.keys[seenKey]; seen && j.DisallowDuplicateKeys {
+					return &DuplicateKeyError{Key: key, Path: append(jsonPath(stack[:len(stack)-1]), key), Offset: dec.InputOffset()}
+				}
+
+				top.keys[seenKey] = struct{}{}
+				top.key = key
+				top.expectKey = false
+				continue
+			}
+		}
+
+		// a value, or the start of one, within an array
+		if top != nil && !top.object && tok != json.Delim(']') && j.MaxArrayLength > 0 && top.index >= j.MaxArrayLength {
+			return &LimitError{Err: ErrArrayTooLong, Max: int64(j.MaxArrayLength), Path: jsonPath(stack[:len(stack)-1]), Offset: dec.InputOffset()}
+		}
+
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			if j.MaxDepth > 0 && len(stack) >= j.MaxDepth {
+				return &LimitError{Err: ErrTooDeep, Max: int64(j.MaxDepth), Path: jsonPath(stack), Offset: dec.InputOffset()}
+			}
+
+			typ := jsonType(reflect.TypeOf(dst))
+			if top != nil {
+				typ = jsonType(top.elem)
+			}
+
+			if tok == json.Delim('{') {
+				stack = append(stack, &jsonFrame{object: true, typ: typ, keys: make(map[string]struct{}), expectKey: true})
+			} else {
+				f := &jsonFrame{typ: typ}
+				if typ != nil && (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) {
+					f.elem = typ.Elem()
+				}
+				stack = append(stack, f)
+			}
+			continue
+		case json.Delim('}'), json.Delim(']'):
+			// the closed object or array is a value of its parent
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 {
+				top = stack[len(stack)-1]
+			} else {
+				top = nil
+			}
+		}
+
+		if top != nil {
+			if top.object {
+				top.expectKey = true
+			} else {
+				top.index++
+			}
+		}
+	}
+}
+
+// returns the path to the current element of the innermost frame
+func jsonPath(stack []*jsonFrame) Path {
+	path := make(Path, 0, len(stack)+1)
+	for _, f := range stack {
+		if f.object {
+			path = append(path, f.key)
+		} else {
+			path = append(path, f.index)
+		}
+	}
+
+	return path
+}
+
+var (
+	jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	jsonFieldCache  sync.Map
+)
+
+// returns the type decoded from a value into t, dereferencing pointers, or nil
+// if it is unknown
+func jsonType(t reflect.Type) reflect.Type {
+	if t == nil {
+		return nil
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() == reflect.Interface || reflect.PtrTo(t).Implements(jsonUnmarshaler) {
+		return nil
+	}
+
+	return t
+}
+
+// returns the types of the fields of a struct by their folded name, in the
+// same way as encoding/json. Fields of the outer struct take precedence over
+// those promoted from embedded structs.
+func jsonFieldsOf(t reflect.Type) map[string]reflect.Type {
+	if jf, ok := jsonFieldCache.Load(t); ok {
+		return jf.(map[string]reflect.Type)
+	}
+
+	jf := make(map[string]reflect.Type)
+	var embedded []reflect.Type
+
+	for i := 0; i < t.NumField(); i++ {
+		ft := t.Field(i)
+
+		name, tagged, skip := fieldName(ft, "json")
+		if skip {
+			continue
+		}
+
+		// untagged embedded structs have their fields promoted
+		if ft.Anonymous && !tagged {
+			et := ft.Type
+			if et.Kind() == reflect.Ptr {
+				et = et.Elem()
+			}
+			if et.Kind() == reflect.Struct {
+				embedded = append(embedded, et)
+				continue
+			}
+		}
+
+		if ft.PkgPath != "" {
+			continue
+		}
+
+		if _, ok := jf[foldName(name)]; !ok {
+			jf[foldName(name)] = ft.Type
+		}
+	}
+
+	for _, et := range embedded {
+		for name, typ := range jsonFieldsOf(et) {
+			if _, ok := jf[name]; !ok {
+				jf[name] = typ
+			}
+		}
+	}
+
+	jsonFieldCache.Store(t, jf)
+	return jf
+}
+
+// returns a name folded in the same way as encoding/json matches keys to
+// fields, each rune is replaced by the smallest rune it is equal to under
+// Unicode simple case folding, so "k", "K" and the Kelvin sign are the same
+func foldName(name string) string {
+	var sb strings.Builder
+	for _, r := range name {
+		for {
+			f := unicode.SimpleFold(r)
+			if f <= r {
+				r = f
+				break
+			}
+			r = f
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,110 @@
+package legit
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type jsonUser struct {
+	Name string      `json:"name"`
+	Tags []jsonUser  `json:"tags"`
+	Any  interface{} `json:"any"`
+}
+
+func TestJSON_Decode_options(t *testing.T) {
+	tests := []struct {
+		Name string
+		JSON JSON
+		Body string
+		Err  error
+	}{
+		{"UnknownAllowed", JSON{}, `{"name":"foo","age":1}`, nil},
+		{"UnknownField", JSON{DisallowUnknownFields: true}, `{"name":"foo","age":1}`, &UnknownFieldError{Field: "age"}},
+		{"TrailingAllowed", JSON{}, `{"name":"foo"} garbage`, nil},
+		{"TrailingWhitespace", JSON{DisallowTrailingData: true}, "{\"name\":\"foo\"} \n", nil},
+		{"TrailingData", JSON{DisallowTrailingData: true}, `{"name":"foo"} garbage`, &TrailingDataError{Offset: 14}},
+		{"TrailingValue", JSON{DisallowTrailingData: true}, `{"name":"foo"}{}`, &TrailingDataError{Offset: 14}},
+		{"DuplicateAllowed", JSON{}, `{"name":"foo","name":"bar"}`, nil},
+		{"DuplicateKey", JSON{DisallowDuplicateKeys: true}, `{"name":"foo","name":"bar"}`, &DuplicateKeyError{Key: "name", Path: Path{"name"}, Offset: 20}},
+		{"DuplicateCase", JSON{DisallowDuplicateKeys: true}, `{"email":"a","Email":"b"}`, &DuplicateKeyError{Key: "Email", Path: Path{"Email"}, Offset: 20}},
+		{"DuplicateNested", JSON{DisallowDuplicateKeys: true}, `{"tags":[{"name":"a"},{"name":"b","name":"c"}]}`, &DuplicateKeyError{Key: "name", Path: Path{"tags", 1, "name"}, Offset: 40}},
+		{"DuplicateInValue", JSON{DisallowDuplicateKeys: true}, `{"any":{"a":{"b":1},"a":[]}}`, &DuplicateKeyError{Key: "a", Path: Path{"any", "a"}, Offset: 23}},
+		{"DistinctObjects", JSON{DisallowDuplicateKeys: true}, `{"name":"a","tags":[{"name":"b"},{"name":"c"}],"any":["name"]}`, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var u jsonUser
+			err := test.JSON.Decode(strings.NewReader(test.Body), &u)
+			if test.Err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, test.Err, err)
+			}
+		})
+	}
+}
+
+func TestJSON_Decode_duplicateKeys(t *testing.T) {
+	type embedded struct {
+		K string
+	}
+
+	tests := []struct {
+		Name string
+		Body string
+		Dst  interface{}
+		Err  error
+	}{
+		{"Map", `{"Content-Type":"a","content-type":"b"}`, &map[string]interface{}{}, nil},
+		{"Interface", `{"any":{"a":1,"A":2}}`, &jsonUser{}, nil},
+		{"Struct", `{"name":"a","NAME":"b"}`, &jsonUser{}, &DuplicateKeyError{Key: "NAME", Path: Path{"NAME"}, Offset: 18}},
+		{"LongS", `{"s":"1","ſ":"2"}`, &struct{ S string }{}, &DuplicateKeyError{Key: "ſ", Path: Path{"ſ"}, Offset: 13}},
+		{"Kelvin", `{"k":"1","\u212a":"2"}`, &struct{ embedded }{}, &DuplicateKeyError{Key: "\u212a", Path: Path{"\u212a"}, Offset: 17}},
+		{"MapOfStructs", `{"a":{"s":"1","S":"2"},"A":{}}`, &map[string]struct{ S string }{}, &DuplicateKeyError{Key: "S", Path: Path{"a", "S"}, Offset: 17}},
+		{"SliceOfMaps", `[{"a":1,"A":2}]`, &[]map[string]int{}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := JSON{DisallowDuplicateKeys: true}.Decode(strings.NewReader(test.Body), test.Dst)
+			if test.Err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, test.Err, err)
+			}
+		})
+	}
+}
+
+func TestJSON_Decode_useNumber(t *testing.T) {
+	var u jsonUser
+	if assert.NoError(t, JSON{UseNumber: true}.Decode(strings.NewReader(`{"any":12345678901234567890}`), &u)) {
+		assert.Equal(t, json.Number("12345678901234567890"), u.Any)
+	}
+
+	if assert.NoError(t, JSON{}.Decode(strings.NewReader(`{"any":1}`), &u)) {
+		assert.Equal(t, float64(1), u.Any)
+	}
+}
+
+func TestJSON_Decode_malformed(t *testing.T) {
+	var u jsonUser
+	err := JSON{DisallowDuplicateKeys: true}.Decode(strings.NewReader(`{"name":`), &u)
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+
+	err = JSON{DisallowDuplicateKeys: true}.Decode(strings.NewReader(`{"name":}`), &u)
+	var se *json.SyntaxError
+	assert.True(t, errors.As(err, &se), "%T", err)
+}
+
+func TestJSONErrors_Error(t *testing.T) {
+	assert.EqualError(t, &UnknownFieldError{Field: "age"}, `json: unknown field "age"`)
+	assert.EqualError(t, &DuplicateKeyError{Key: "name", Path: Path{"tags", 1, "name"}, Offset: 41}, `json: duplicate key "tags[1].name" at offset 41`)
+	assert.EqualError(t, &TrailingDataError{Offset: 14}, "unexpected data after offset 14")
+}
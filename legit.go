@@ -1,8 +1,11 @@
 package legit
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"sort"
 )
 
 var (
@@ -11,12 +14,20 @@ var (
 	ErrNotStruct = errors.New("object is not a struct")
 
 	// ErrNotSlice is returned when a slice validation method is not given
-	// a slice.
+	// a slice or array.
 	ErrNotSlice = errors.New("object is not a slice")
 
+	// ErrNotMap is returned when a map validation method is not given
+	// a map.
+	ErrNotMap = errors.New("object is not a map")
+
 	// ErrStrict is returned when strict validation mode is enabled and a
 	// field does not satisfy the Validator interface.
 	ErrStrict = errors.New("field is not a validator")
+
+	// ErrPointerReceiver is returned when strict receiver mode is enabled and
+	// a field only satisfies the Validator interface through its pointer.
+	ErrPointerReceiver = errors.New("validator has pointer receiver")
 )
 
 // Validator is a type that can be validated
@@ -27,19 +38,59 @@ type Validator interface {
 
 var validator = reflect.TypeOf((*Validator)(nil)).Elem()
 
+// ValidatorContext is a type that can be validated with a request scoped
+// context, it is preferred over Validator when a type implements both
+type ValidatorContext interface {
+	// returns nil if object is valid
+	ValidateContext(ctx context.Context) error
+}
+
+var validatorContext = reflect.TypeOf((*ValidatorContext)(nil)).Elem()
+
 var legit = New()
 
 // Legit implements validation of types implementing the Validator interface,
-// structs and slices.
+// structs, slices and maps.
 type Legit struct {
 	// Strict mode requires that all fields in a struct be validatable
 	Strict bool
+
+	// StrictReceivers rejects types whose Validate method has a pointer
+	// receiver, rather than taking the address of the value to call it
+	StrictReceivers bool
+
+	// MaxDepth limits how deeply nested structs, slices and maps are
+	// validated, zero disables the limit
+	MaxDepth int
+
+	// FailFast stops validation at the first error encountered
+	FailFast bool
+
+	// TagName is the struct tag, such as json or xml, used to name fields in
+	// StructError. When empty, or a field has no such tag, the Go field name
+	// is used.
+	TagName string
+
+	// MaxErrors stops validation once this many errors have been collected,
+	// marking the returned Errors as truncated if more were found. Zero
+	// disables the limit.
+	MaxErrors int
+
+	// SummarizeErrors returns failures as a SummaryError, whose Error method
+	// summarizes every failure rather than only the first
+	SummarizeErrors bool
 }
 
 // New return a Legit assignment without strict validation
 func New() Legit {
 	return Legit{
-		Strict: false,
+		Strict:          false,
+		StrictReceivers: false,
+		MaxDepth:        0,
+		FailFast:        false,
+		MaxErrors:       0,
+		TagName:         "",
+		SummarizeErrors: false,
 	}
 }
 
@@ -48,44 +99,45 @@ func Validate(src interface{}) error {
 }
 
 func (l Legit) Validate(src interface{}) error {
+	return l.ValidateContext(context.Background(), src)
+}
+
+// ValidateContext validates src, passing ctx to any ValidatorContext
+// encountered. Validation stops early and returns the context's error if it
+// is cancelled.
+func ValidateContext(ctx context.Context, src interface{}) error {
+	return legit.ValidateContext(ctx, src)
+}
+
+// ValidateContext validates src, passing ctx to any ValidatorContext
+// encountered. Validation stops early and returns the context's error if it
+// is cancelled.
+func (l Legit) ValidateContext(ctx context.Context, src interface{}) error {
 	// prevent Validation methods being called on nil pointers
 	if src == nil {
 		return nil
 	}
 
 	// skip reflection if src implements custom Validator interface
-	if obj, ok := src.(Validator); ok {
+	if obj, ok := src.(ValidatorContext); ok {
+		return obj.ValidateContext(ctx)
+	} else if obj, ok := src.(Validator); ok {
 		return obj.Validate()
 	}
 
-	objv := resolvePointer(reflect.ValueOf(src))
+	w := l.walker(ctx)
+	objv := w.resolve(reflect.ValueOf(src))
+	if !objv.IsValid() {
+		return nil
+	}
 	objt := objv.Type()
 
-	return l.validate(objv, objt)
+	return w.finish(w.validate(objv, objt))
 }
 
 func (l Legit) validate(objv reflect.Value, objt reflect.Type) error {
-	// don't attempt to validate pointers (optional fields)
-	if objv.Kind() == reflect.Ptr && objv.IsNil() {
-		return nil
-	}
-
-	if objt.Implements(validator) {
-		return objv.Interface().(Validator).Validate()
-	}
-
-	switch objv.Kind() {
-	case reflect.Struct:
-		return l.validateStruct(objv, objt)
-	case reflect.Slice:
-		return l.validateSlice(objv, objt)
-	}
-
-	if l.Strict {
-		return ErrStrict
-	}
-
-	return nil
+	w := l.walker(context.Background())
+	return w.finish(w.validate(objv, objt))
 }
 
 func ValidateStruct(src interface{}) error {
@@ -93,38 +145,19 @@ func ValidateStruct(src interface{}) error {
 }
 
 func (l Legit) ValidateStruct(src interface{}) error {
-	objv := resolvePointer(reflect.ValueOf(src))
+	w := l.walker(context.Background())
+	objv := w.resolve(reflect.ValueOf(src))
 	if objv.Kind() != reflect.Struct {
 		return ErrNotStruct
 	}
 	objt := objv.Type()
 
-	return l.validateStruct(objv, objt)
+	return w.finish(w.validateStruct(objv, objt))
 }
 
 func (l Legit) validateStruct(objv reflect.Value, objt reflect.Type) error {
-	var errors Errors
-
-	for i := 0; i < objt.NumField(); i++ {
-		ft := objt.Field(i)
-
-		// see reflect StructField.PkgPath for determining if field is exported
-		// TODO: is there a better way to determine if a field is exported?
-		if len(ft.PkgPath) < 1 {
-			fv := objv.Field(i)
-
-			err := l.validate(fv, fv.Type())
-			if err != nil {
-				errors = append(errors, StructError{Field: ft.Name, Message: err})
-			}
-		}
-	}
-
-	if len(errors) > 0 {
-		return errors
-	}
-
-	return nil
+	w := l.walker(context.Background())
+	return w.finish(w.validateStruct(objv, objt))
 }
 
 func ValidateSlice(src interface{}) error {
@@ -132,35 +165,60 @@ func ValidateSlice(src interface{}) error {
 }
 
 func (l Legit) ValidateSlice(src interface{}) error {
-	objv := resolvePointer(reflect.ValueOf(src))
-	if objv.Kind() != reflect.Slice {
+	w := l.walker(context.Background())
+	objv := w.resolve(reflect.ValueOf(src))
+	if objv.Kind() != reflect.Slice && objv.Kind() != reflect.Array {
 		return ErrNotSlice
 	}
 	objt := objv.Type()
 
-	return l.validateSlice(objv, objt)
+	return w.finish(w.validateSlice(objv, objt))
 }
 
 func (l Legit) validateSlice(objv reflect.Value, objt reflect.Type) error {
-	if objv.Len() < 1 {
-		return nil
-	}
+	w := l.walker(context.Background())
+	return w.finish(w.validateSlice(objv, objt))
+}
 
-	var errors Errors
+func ValidateMap(src interface{}) error {
+	return legit.ValidateMap(src)
+}
 
-	for i := 0; i < objv.Len(); i++ {
-		iv := objv.Index(i)
-		err := l.validate(iv, iv.Type())
-		if err != nil {
-			errors = append(errors, SliceError{Index: i, Message: err})
-		}
+func (l Legit) ValidateMap(src interface{}) error {
+	w := l.walker(context.Background())
+	objv := w.resolve(reflect.ValueOf(src))
+	if objv.Kind() != reflect.Map {
+		return ErrNotMap
 	}
+	objt := objv.Type()
 
-	if len(errors) > 0 {
-		return errors
-	}
+	return w.finish(w.validateMap(objv, objt))
+}
 
-	return nil
+func (l Legit) validateMap(objv reflect.Value, objt reflect.Type) error {
+	w := l.walker(context.Background())
+	return w.finish(w.validateMap(objv, objt))
+}
+
+// sort map keys by their natural order where possible, otherwise by their
+// string representation
+func sortKeys(keys []reflect.Value) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+
+		switch a.Kind() {
+		case reflect.String:
+			return a.String() < b.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return a.Int() < b.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return a.Uint() < b.Uint()
+		case reflect.Float32, reflect.Float64:
+			return a.Float() < b.Float()
+		}
+
+		return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+	})
 }
 
 // return concrete type from arbitrary pointer depth
@@ -175,3 +233,33 @@ func resolvePointer(objv reflect.Value) reflect.Value {
 
 	return objv
 }
+
+// returns true if a type implements either Validator or ValidatorContext
+func isValidator(t reflect.Type) bool {
+	return t.Implements(validatorContext) || t.Implements(validator)
+}
+
+// returns an addressable copy of a value if it is not already addressable
+func addressable(objv reflect.Value) reflect.Value {
+	if objv.CanAddr() {
+		return objv
+	}
+
+	v := reflect.New(objv.Type()).Elem()
+	v.Set(objv)
+	return v
+}
+
+// returns true if an embedded field is a struct, or pointer to a struct, whose
+// fields are promoted rather than being validated as a whole
+func isEmbeddedStruct(t reflect.Type) bool {
+	if isValidator(t) || isValidator(reflect.PtrTo(t)) {
+		return false
+	}
+
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct
+}
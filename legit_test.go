@@ -1,6 +1,8 @@
 package legit
 
 import (
+	"context"
+	"errors"
 	"reflect"
 	"testing"
 
@@ -10,6 +12,12 @@ import (
 func TestLegit_New(t *testing.T) {
 	v := New()
 	assert.False(t, v.Strict)
+	assert.False(t, v.StrictReceivers)
+	assert.Equal(t, 0, v.MaxDepth)
+	assert.False(t, v.FailFast)
+	assert.Equal(t, 0, v.MaxErrors)
+	assert.Equal(t, "", v.TagName)
+	assert.False(t, v.SummarizeErrors)
 }
 
 func TestValidate(t *testing.T) {
@@ -17,14 +25,14 @@ func TestValidate(t *testing.T) {
 		Name Lower
 	}{"FOO"})
 	if assert.NotNil(t, err) {
-		assert.Equal(t, Errors{StructError{Field: "Name", Message: errLower}}, err)
+		assert.Equal(t, Errors{StructError{Field: "Name", Message: ErrLower}}, err)
 	}
 }
 
 func TestLegit_Validate(t *testing.T) {
 	err := legit.Validate([]Lower{"FOO"})
 	if assert.NotNil(t, err) {
-		assert.Equal(t, Errors{SliceError{Index: 0, Message: errLower}}, err)
+		assert.Equal(t, Errors{SliceError{Index: 0, Message: ErrLower}}, err)
 	}
 }
 
@@ -34,23 +42,86 @@ func TestLegit_Validate_customValidator(t *testing.T) {
 
 	err = legit.Validate(Lower("FOO"))
 	if assert.NotNil(t, err) {
-		assert.Equal(t, errLower, err)
+		assert.Equal(t, ErrLower, err)
 	}
 }
 
+type tenantKey struct{}
+
+type tenantName string
+
+func (n tenantName) Validate() error {
+	return errors.New("Validate should not be called")
+}
+
+func (n tenantName) ValidateContext(ctx context.Context) error {
+	if tenant, _ := ctx.Value(tenantKey{}).(string); tenant != string(n) {
+		return errors.New("wrong tenant")
+	}
+
+	return nil
+}
+
+func TestValidateContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), tenantKey{}, "foo")
+
+	err := ValidateContext(ctx, tenantName("foo"))
+	assert.NoError(t, err)
+
+	err = ValidateContext(ctx, struct {
+		Names []tenantName
+	}{[]tenantName{"foo", "bar"}})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{StructError{Field: "Names", Message: Errors{SliceError{Index: 1, Message: errors.New("wrong tenant")}}}}, err)
+	}
+}
+
+func TestLegit_ValidateContext_cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := legit.ValidateContext(ctx, []Lower{"FOO"})
+	assert.Equal(t, context.Canceled, err)
+
+	err = legit.ValidateContext(ctx, (*struct{ Name Lower })(nil))
+	assert.NoError(t, err)
+}
+
 func TestLegit_validate_struct(t *testing.T) {
 	err := legit.validate(reflected(struct {
 		Name Lower
 	}{"FOO"}))
 	if assert.NotNil(t, err) {
-		assert.Equal(t, Errors{StructError{Field: "Name", Message: errLower}}, err)
+		assert.Equal(t, Errors{StructError{Field: "Name", Message: ErrLower}}, err)
 	}
 }
 
 func TestLegit_validate_slice(t *testing.T) {
 	err := legit.validate(reflected([]Lower{"FOO"}))
 	if assert.NotNil(t, err) {
-		assert.Equal(t, Errors{SliceError{Index: 0, Message: errLower}}, err)
+		assert.Equal(t, Errors{SliceError{Index: 0, Message: ErrLower}}, err)
+	}
+}
+
+func TestLegit_validate_array(t *testing.T) {
+	err := legit.validate(reflected([2]Lower{"foo", "FOO"}))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{SliceError{Index: 1, Message: ErrLower}}, err)
+	}
+}
+
+func TestLegit_validate_interface(t *testing.T) {
+	err := legit.validate(reflected(struct {
+		Name  interface{}
+		Other Validator
+	}{Lower("FOO"), nil}))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{StructError{Field: "Name", Message: ErrLower}}, err)
+	}
+
+	err = legit.validate(reflected([]interface{}{Lower("foo"), nil, []Lower{"FOO"}}))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{SliceError{Index: 2, Message: Errors{SliceError{Index: 0, Message: ErrLower}}}}, err)
 	}
 }
 
@@ -60,7 +131,7 @@ func TestLegit_validate_customValidator(t *testing.T) {
 
 	err = legit.validate(reflected(Lower("FOO")))
 	if assert.NotNil(t, err) {
-		assert.Equal(t, errLower, err)
+		assert.Equal(t, ErrLower, err)
 	}
 }
 
@@ -70,6 +141,68 @@ func TestLegit_validate_strict(t *testing.T) {
 	assert.Equal(t, ErrStrict, err)
 }
 
+type pointerName string
+
+func (n *pointerName) Validate() error {
+	return Lower(*n).Validate()
+}
+
+func TestLegit_validate_pointerReceiver(t *testing.T) {
+	err := legit.validate(reflected(pointerName("FOO")))
+	assert.Equal(t, ErrLower, err)
+
+	err = legit.validate(reflected(struct {
+		Name pointerName
+	}{"FOO"}))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{StructError{Field: "Name", Message: ErrLower}}, err)
+	}
+
+	err = legit.validate(reflected([]pointerName{"foo", "FOO"}))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{SliceError{Index: 1, Message: ErrLower}}, err)
+	}
+
+	err = legit.validate(reflected(map[string]pointerName{"a": "FOO"}))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{MapError{Key: "a", Message: ErrLower}}, err)
+	}
+
+	err = Validate(map[pointerName]string{"foo": "a", "FOO": "b"})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{MapError{Key: "FOO", OnKey: true, Message: ErrLower}}, err)
+	}
+}
+
+func TestLegit_validate_strictReceivers(t *testing.T) {
+	l := Legit{StrictReceivers: true}
+	err := l.validate(reflected(pointerName("foo")))
+	assert.Equal(t, ErrPointerReceiver, err)
+
+	name := pointerName("foo")
+	err = l.validate(reflected(&name))
+	assert.NoError(t, err)
+}
+
+func TestLegit_Validate_tagName(t *testing.T) {
+	l := Legit{TagName: "json"}
+	err := l.Validate(struct {
+		embeddedName
+		Users []struct {
+			Email Email `json:"email"`
+		} `json:"users"`
+	}{embeddedName{"FOO"}, []struct {
+		Email Email `json:"email"`
+	}{{"foo"}}})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{
+			StructError{Field: "Name", Message: ErrLower},
+			StructError{Field: "users", Message: Errors{SliceError{Index: 0, Message: Errors{StructError{Field: "email", Message: ErrEmail}}}}},
+		}, err)
+		assert.Equal(t, "users[0].email", err.(Errors).Flatten()[1].Path.String())
+	}
+}
+
 func TestLegit_validate_unknown(t *testing.T) {
 	err := legit.validate(reflected("foo"))
 	assert.NoError(t, err)
@@ -80,7 +213,7 @@ func TestValidateStruct(t *testing.T) {
 		Name Lower
 	}{"FOO"})
 	if assert.NotNil(t, err) {
-		assert.Equal(t, Errors{StructError{Field: "Name", Message: errLower}}, err)
+		assert.Equal(t, Errors{StructError{Field: "Name", Message: ErrLower}}, err)
 	}
 
 }
@@ -93,7 +226,7 @@ func TestLegit_ValidateStruct(t *testing.T) {
 		Name Lower
 	}{"FOO"})
 	if assert.NotNil(t, err) {
-		assert.Equal(t, Errors{StructError{Field: "Name", Message: errLower}}, err)
+		assert.Equal(t, Errors{StructError{Field: "Name", Message: ErrLower}}, err)
 	}
 }
 
@@ -109,14 +242,49 @@ func TestLegit_validateStruct(t *testing.T) {
 		Last  Lower
 	}{"foo", "FOO"}))
 	if assert.NotNil(t, err) {
-		assert.Equal(t, Errors{StructError{Field: "Last", Message: errLower}}, err)
+		assert.Equal(t, Errors{StructError{Field: "Last", Message: ErrLower}}, err)
+	}
+}
+
+type embeddedName struct {
+	Name Lower
+}
+
+type EmbeddedEmail struct {
+	Email Email
+}
+
+func TestLegit_validateStruct_embedded(t *testing.T) {
+	err := legit.validateStruct(reflected(struct {
+		embeddedName
+		*EmbeddedEmail
+		Age Positive
+	}{embeddedName{"FOO"}, &EmbeddedEmail{"foo"}, -1}))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{
+			StructError{Field: "Name", Message: ErrLower},
+			StructError{Field: "Email", Message: ErrEmail},
+			StructError{Field: "Age", Message: ErrPositive},
+		}, err)
+	}
+
+	err = legit.validateStruct(reflected(struct {
+		*EmbeddedEmail
+	}{}))
+	assert.NoError(t, err)
+
+	err = legit.validateStruct(reflected(struct {
+		Lower
+	}{"FOO"}))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{StructError{Field: "Lower", Message: ErrLower}}, err)
 	}
 }
 
 func TestValidateSlice(t *testing.T) {
 	err := ValidateSlice([]Lower{"FOO"})
 	if assert.NotNil(t, err) {
-		assert.Equal(t, Errors{SliceError{Index: 0, Message: errLower}}, err)
+		assert.Equal(t, Errors{SliceError{Index: 0, Message: ErrLower}}, err)
 	}
 }
 
@@ -124,9 +292,14 @@ func TestLegit_ValidateSlice(t *testing.T) {
 	err := legit.ValidateSlice(Lower("foo"))
 	assert.Equal(t, ErrNotSlice, err)
 
+	err = legit.ValidateSlice([1]Lower{"FOO"})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{SliceError{Index: 0, Message: ErrLower}}, err)
+	}
+
 	err = legit.ValidateSlice([]Lower{"FOO"})
 	if assert.NotNil(t, err) {
-		assert.Equal(t, Errors{SliceError{Index: 0, Message: errLower}}, err)
+		assert.Equal(t, Errors{SliceError{Index: 0, Message: ErrLower}}, err)
 	}
 }
 
@@ -139,7 +312,65 @@ func TestLegit_validateSlice(t *testing.T) {
 
 	err = legit.validateSlice(reflected([]Lower{"foo", "FOO"}))
 	if assert.NotNil(t, err) {
-		assert.Equal(t, Errors{SliceError{Index: 1, Message: errLower}}, err)
+		assert.Equal(t, Errors{SliceError{Index: 1, Message: ErrLower}}, err)
+	}
+}
+
+func TestValidateMap(t *testing.T) {
+	err := ValidateMap(map[string]Lower{"a": "FOO"})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{MapError{Key: "a", Message: ErrLower}}, err)
+	}
+}
+
+func TestLegit_ValidateMap(t *testing.T) {
+	err := legit.ValidateMap(Lower("foo"))
+	assert.Equal(t, ErrNotMap, err)
+
+	err = legit.ValidateMap(map[string]Lower{"a": "FOO"})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{MapError{Key: "a", Message: ErrLower}}, err)
+	}
+}
+
+func TestLegit_validate_map(t *testing.T) {
+	err := legit.validate(reflected(map[string]Lower{"a": "FOO"}))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{MapError{Key: "a", Message: ErrLower}}, err)
+	}
+
+	l := Legit{Strict: true}
+	err = l.validate(reflected(map[string]Lower{"a": "foo"}))
+	assert.NoError(t, err)
+}
+
+func TestLegit_validateMap(t *testing.T) {
+	err := legit.validateMap(reflected(map[string]Lower{}))
+	assert.NoError(t, err)
+
+	err = legit.validateMap(reflected(map[string]Lower{"a": "foo"}))
+	assert.NoError(t, err)
+
+	err = legit.validateMap(reflected(map[string]Lower{"d": "FOO", "a": "foo", "c": "BAR", "b": "BAZ"}))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{
+			MapError{Key: "b", Message: ErrLower},
+			MapError{Key: "c", Message: ErrLower},
+			MapError{Key: "d", Message: ErrLower},
+		}, err)
+	}
+
+	err = legit.validateMap(reflected(map[int]Lower{10: "FOO", 9: "BAR"}))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{
+			MapError{Key: "9", Message: ErrLower},
+			MapError{Key: "10", Message: ErrLower},
+		}, err)
+	}
+
+	err = legit.validateMap(reflected(map[Lower]Lower{"FOO": "foo"}))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{MapError{Key: "FOO", OnKey: true, Message: ErrLower}}, err)
 	}
 }
 
@@ -0,0 +1,103 @@
+package legit
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrBodyTooLarge is returned when a body is larger than
+	// Form.MaxBodyBytes.
+	ErrBodyTooLarge = NewValidationError("limit.body_too_large", "body is larger than {max} bytes", nil)
+
+	// ErrTooDeep is returned when a body is nested deeper than
+	// Limits.MaxDepth.
+	ErrTooDeep = NewValidationError("limit.too_deep", "nested deeper than {max} levels", nil)
+
+	// ErrStringTooLong is returned when a string in a body is longer than
+	// Limits.MaxStringLength.
+	ErrStringTooLong = NewValidationError("limit.string_too_long", "string is longer than {max} characters", nil)
+
+	// ErrArrayTooLong is returned when an array in a body is longer than
+	// Limits.MaxArrayLength.
+	ErrArrayTooLong = NewValidationError("limit.array_too_long", "array has more than {max} elements", nil)
+
+	// ErrTooManyTokens is returned when an XML body contains more tokens than
+	// XML.MaxTokens.
+	ErrTooManyTokens = NewValidationError("limit.too_many_tokens", "body has more than {max} tokens", nil)
+
+	// ErrTooManyAttributes is returned when an XML element has more attributes
+	// than XML.MaxAttributes.
+	ErrTooManyAttributes = NewValidationError("limit.too_many_attributes", "element has more than {max} attributes", nil)
+)
+
+// Limits restricts the complexity of a body, checked before it is decoded.
+// Zero disables a limit.
+type Limits struct {
+	// MaxDepth is the maximum nesting of objects and arrays, or elements
+	MaxDepth int
+
+	// MaxStringLength is the maximum length of a string, in characters
+	MaxStringLength int
+
+	// MaxArrayLength is the maximum number of elements in an array, or of
+	// repeated child elements of the same name
+	MaxArrayLength int
+}
+
+// returns true if any limit is enabled
+func (l Limits) enabled() bool {
+	return l.MaxDepth > 0 || l.MaxStringLength > 0 || l.MaxArrayLength > 0
+}
+
+// returns the limits with those set in o taking precedence
+func (l Limits) merge(o Limits) Limits {
+	if o.MaxDepth > 0 {
+		l.MaxDepth = o.MaxDepth
+	}
+	if o.MaxStringLength > 0 {
+		l.MaxStringLength = o.MaxStringLength
+	}
+	if o.MaxArrayLength > 0 {
+		l.MaxArrayLength = o.MaxArrayLength
+	}
+
+	return l
+}
+
+// LimitError is returned when a body exceeds a limit. Err is one of
+// ErrBodyTooLarge, ErrTooDeep, ErrStringTooLong or ErrArrayTooLong, allowing
+// the limit to be identified with errors.Is. Path is the location within the
+// body of the value which exceeded the limit, if known.
+type LimitError struct {
+	Err    *ValidationError
+	Max    int64
+	Path   Path
+	Offset int64
+}
+
+// returns the string representation of the exceeded limit
+func (le *LimitError) Error() string {
+	msg := substitute(le.Err.Message, map[string]interface{}{"max": le.Max})
+	if len(le.Path) > 0 {
+		return fmt.Sprintf("%s: %s", le.Path, msg)
+	}
+
+	return msg
+}
+
+// returns the sentinel error of the exceeded limit
+func (le *LimitError) Unwrap() error {
+	return le.Err
+}
+
+// returns a LimitError in place of the error returned by http.MaxBytesReader
+func bodyTooLarge(err error) error {
+	var mbe *http.MaxBytesError
+	if errors.As(err, &mbe) {
+		return &LimitError{Err: ErrBodyTooLarge, Max: mbe.Limit}
+	}
+
+	return err
+}
@@ -0,0 +1,132 @@
+package legit
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimits_merge(t *testing.T) {
+	l := Limits{MaxDepth: 1, MaxStringLength: 2}
+	assert.Equal(t, Limits{MaxDepth: 1, MaxStringLength: 3, MaxArrayLength: 4}, l.merge(Limits{MaxStringLength: 3, MaxArrayLength: 4}))
+	assert.Equal(t, l, l.merge(Limits{}))
+	assert.False(t, Limits{}.enabled())
+	assert.True(t, Limits{MaxArrayLength: 1}.enabled())
+}
+
+func TestLimitError_Error(t *testing.T) {
+	assert.EqualError(t, &LimitError{Err: ErrBodyTooLarge, Max: 10}, "body is larger than 10 bytes")
+	assert.EqualError(t, &LimitError{Err: ErrArrayTooLong, Max: 2, Path: Path{"tags"}}, "tags: array has more than 2 elements")
+	assert.True(t, errors.Is(&LimitError{Err: ErrTooDeep, Max: 2}, ErrTooDeep))
+	assert.False(t, errors.Is(&LimitError{Err: ErrTooDeep, Max: 2}, ErrArrayTooLong))
+}
+
+func TestJSON_Decode_limits(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Limits Limits
+		Body   string
+		Err    error
+	}{
+		{"Within", Limits{MaxDepth: 3, MaxStringLength: 4, MaxArrayLength: 2}, `{"tags":[{"name":"foo"},{"name":"bar"}]}`, nil},
+		{"Depth", Limits{MaxDepth: 2}, `{"tags":[{"name":"foo"}]}`, &LimitError{Err: ErrTooDeep, Max: 2, Path: Path{"tags", 0}, Offset: 10}},
+		{"String", Limits{MaxStringLength: 4}, `{"tags":[{"name":"foo"},{"name":"fööög"}]}`, &LimitError{Err: ErrStringTooLong, Max: 4, Path: Path{"tags", 1, "name"}, Offset: 42}},
+		{"Key", Limits{MaxStringLength: 4}, `{"tags":[{"names":"foo"}]}`, &LimitError{Err: ErrStringTooLong, Max: 4, Path: Path{"tags", 0}, Offset: 17}},
+		{"Array", Limits{MaxArrayLength: 1}, `{"tags":[{"name":"a"},{"name":"b"}]}`, &LimitError{Err: ErrArrayTooLong, Max: 1, Path: Path{"tags"}, Offset: 23}},
+		{"NestedArray", Limits{MaxArrayLength: 2}, `{"any":[[1,2],[3,4,5]]}`, &LimitError{Err: ErrArrayTooLong, Max: 2, Path: Path{"any", 1}, Offset: 20}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var u jsonUser
+			err := JSON{Limits: test.Limits}.Decode(strings.NewReader(test.Body), &u)
+			if test.Err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, test.Err, err)
+			}
+		})
+	}
+}
+
+type xmlLimitUser struct {
+	Name string   `xml:"name,attr"`
+	Tags []string `xml:"tags>tag"`
+}
+
+func TestXML_Decode_limits(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Limits Limits
+		Body   string
+		Err    error
+	}{
+		{"Within", Limits{MaxDepth: 3, MaxStringLength: 3, MaxArrayLength: 2}, `<user name="foo"><tags><tag>a</tag><tag>b</tag></tags></user>`, nil},
+		{"Depth", Limits{MaxDepth: 2}, `<user><tags><tag>a</tag></tags></user>`, &LimitError{Err: ErrTooDeep, Max: 2, Path: Path{"tags", "tag"}, Offset: 17}},
+		{"Attr", Limits{MaxStringLength: 3}, `<user name="fööö"></user>`, &LimitError{Err: ErrStringTooLong, Max: 3, Path: Path{"name"}, Offset: 21}},
+		{"CharData", Limits{MaxStringLength: 3}, `<user><tags><tag>abcd</tag></tags></user>`, &LimitError{Err: ErrStringTooLong, Max: 3, Path: Path{"tags", "tag"}, Offset: 21}},
+		{"Array", Limits{MaxArrayLength: 1}, `<user><tags><tag>a</tag><tag>b</tag></tags></user>`, &LimitError{Err: ErrArrayTooLong, Max: 1, Path: Path{"tags", "tag"}, Offset: 29}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var u xmlLimitUser
+			err := XML{Limits: test.Limits}.Decode(strings.NewReader(test.Body), &u)
+			if test.Err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, test.Err, err)
+			}
+		})
+	}
+}
+
+func TestForm_ParseAndValidate_limits(t *testing.T) {
+	f := NewForm()
+	f.MaxBodyBytes = 16
+	f.MaxArrayLength = 1
+
+	var u jsonUser
+	err := f.ParseAndValidate(strings.NewReader(`{"name":"foo","tags":[]}`), "application/json", &u)
+	assert.True(t, errors.Is(err, ErrBodyTooLarge), err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, NewProblem(err).Status)
+	assert.EqualError(t, err, "body is larger than 16 bytes")
+
+	err = f.ParseAndValidate(strings.NewReader(`{"any":[1,2]}`), "application/json", &u)
+	assert.True(t, errors.Is(err, ErrArrayTooLong), err)
+
+	p := NewProblem(err)
+	assert.Equal(t, http.StatusBadRequest, p.Status)
+	assert.Equal(t, ProblemErrors{{Pointer: "/any", Field: "any", Code: "limit.array_too_long", Message: "array has more than 1 elements"}}, p.Errors)
+
+	assert.Equal(t, JSON{Limits: Limits{MaxDepth: 2, MaxArrayLength: 1}}, Form{Decoders: Decoders{JSON{Limits: Limits{MaxDepth: 2}}}, Limits: f.Limits}.decoder("application/json"))
+	assert.Equal(t, XML{Limits: Limits{MaxArrayLength: 1}}, Form{Decoders: Decoders{XML{}}, Limits: f.Limits}.decoder("application/xml"))
+}
+
+func TestForm_ParseRequestAndValidate_maxBodyBytes(t *testing.T) {
+	f := NewForm()
+	f.Decoders = Decoders{JSON{}, Multipart{}}
+	f.MaxBodyBytes = 64
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"`+strings.Repeat("a", 64)+`"}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	var u jsonUser
+	err := f.ParseRequestAndValidate(r, &u)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, NewProblem(err).Status)
+
+	body, ct := multipartBody(t, func(w *multipart.Writer) {
+		w.WriteField("name", strings.Repeat("a", 64))
+	})
+	r = httptest.NewRequest(http.MethodPost, "/", body)
+	r.Header.Set("Content-Type", ct)
+
+	var mu multipartUser
+	err = f.ParseRequestAndValidate(r, &mu)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, NewProblem(err).Status)
+}
@@ -0,0 +1,314 @@
+package legit
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// errorNode is the serialized form of a validation error, shared by the JSON
+// and XML encodings. Struct, slice and map errors are identified by their
+// field, index or key. Leaf failures carry a message, and the code and
+// parameters of a ValidationError, while errors containing further failures
+// list them as children. ErrTruncated is encoded as a node with only truncated
+// set, rather than as a failure.
+type errorNode struct {
+	XMLName   xml.Name               `json:"-" xml:"error"`
+	Field     string                 `json:"field,omitempty" xml:"field,attr,omitempty"`
+	Index     *int                   `json:"index,omitempty" xml:"index,attr,omitempty"`
+	Key       *string                `json:"key,omitempty" xml:"key,attr,omitempty"`
+	OnKey     bool                   `json:"on_key,omitempty" xml:"on_key,attr,omitempty"`
+	Truncated bool                   `json:"truncated,omitempty" xml:"truncated,attr,omitempty"`
+	Code      string                 `json:"code,omitempty" xml:"code,attr,omitempty"`
+	Message   string                 `json:"message,omitempty" xml:"message,omitempty"`
+	Params    map[string]interface{} `json:"params,omitempty" xml:"-"`
+	XMLParams []errorParam           `json:"-" xml:"param"`
+	Errors    []errorNode            `json:"errors,omitempty" xml:"error"`
+}
+
+// errorParam is a parameter of a ValidationError, encoding/xml does not
+// support maps
+type errorParam struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+// return the serialized form of an error
+func nodeOf(err error) errorNode {
+	if err == ErrTruncated {
+		return errorNode{Truncated: true}
+	}
+
+	switch e := err.(type) {
+	case StructError:
+		n := messageNode(e.Message)
+		n.Field = e.Field
+		return n
+	case SliceError:
+		n := messageNode(e.Message)
+		n.Index = &e.Index
+		return n
+	case MapError:
+		n := messageNode(e.Message)
+		n.Key = &e.Key
+		n.OnKey = e.OnKey
+		return n
+	}
+
+	return messageNode(err)
+}
+
+// return the serialized form of the message of a struct, slice or map error
+func messageNode(err error) errorNode {
+	switch e := err.(type) {
+	case Errors:
+		return errorNode{Errors: nodesOf(e)}
+	case StructError, SliceError, MapError:
+		return errorNode{Errors: []errorNode{nodeOf(e)}}
+	case nil:
+		return errorNode{}
+	}
+
+	n := errorNode{Message: err.Error()}
+
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		n.Code = ve.Code
+		n.Params = ve.Params
+		for _, name := range sortedParams(ve.Params) {
+			n.XMLParams = append(n.XMLParams, errorParam{Name: name, Value: fmt.Sprint(ve.Params[name])})
+		}
+	}
+
+	return n
+}
+
+func sortedParams(params map[string]interface{}) []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func nodesOf(errs Errors) []errorNode {
+	nodes := make([]errorNode, len(errs))
+	for i, err := range errs {
+		nodes[i] = nodeOf(err)
+	}
+
+	return nodes
+}
+
+// return the error represented by a serialized error
+func (n errorNode) error() error {
+	switch {
+	case n.Truncated:
+		return ErrTruncated
+	case n.Field != "":
+		return StructError{Field: n.Field, Message: n.message()}
+	case n.Index != nil:
+		return SliceError{Index: *n.Index, Message: n.message()}
+	case n.Key != nil:
+		return MapError{Key: *n.Key, OnKey: n.OnKey, Message: n.message()}
+	}
+
+	return n.message()
+}
+
+// return the message of a serialized error, either the children it contains
+// or a leaf failure
+func (n errorNode) message() error {
+	if len(n.Errors) > 0 {
+		return errorsOf(n.Errors)
+	}
+
+	if n.Code != "" {
+		params := n.Params
+		for _, p := range n.XMLParams {
+			if params == nil {
+				params = make(map[string]interface{})
+			}
+			params[p.Name] = p.Value
+		}
+
+		return NewValidationError(n.Code, n.Message, params)
+	}
+
+	return errors.New(n.Message)
+}
+
+func errorsOf(nodes []errorNode) Errors {
+	errs := make(Errors, len(nodes))
+	for i, n := range nodes {
+		errs[i] = n.error()
+	}
+
+	return errs
+}
+
+// MarshalJSON encodes Errors as an array of objects. Struct, slice and map
+// errors are identified by a "field", "index" or "key" member respectively,
+// "on_key" is set when a map key failed validation. Leaf failures have a
+// "message" member, and "code" and "params" members when the failure is a
+// ValidationError, while errors containing further failures list them in an
+// "errors" member. When validation stopped at Legit.MaxErrors, the array ends
+// with an object whose only member is "truncated". For example:
+//
+//	[{"field": "users", "errors": [{"index": 3, "errors": [{"field": "email", "code": "email.invalid", "message": "invalid email"}]}]}, {"truncated": true}]
+func (e Errors) MarshalJSON() ([]byte, error) {
+	return json.Marshal(nodesOf(e))
+}
+
+// UnmarshalJSON rebuilds Errors from the encoding produced by MarshalJSON.
+// Leaf failures with a code are reconstructed as a ValidationError, otherwise
+// as errors with the same message.
+func (e *Errors) UnmarshalJSON(data []byte) error {
+	var nodes []errorNode
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		return err
+	}
+
+	*e = errorsOf(nodes)
+	return nil
+}
+
+// MarshalXML encodes Errors as an "errors" element containing an "error"
+// element for each failure, using the same attributes as the members produced
+// by MarshalJSON. Leaf failures contain a "message" element, and a "param"
+// element for each parameter of a ValidationError. Truncated errors end with
+// an empty "error" element with the "truncated" attribute.
+func (e Errors) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	if start.Name.Local == "" || start.Name.Local == "Errors" {
+		start.Name = xml.Name{Local: "errors"}
+	}
+
+	return enc.EncodeElement(struct {
+		Errors []errorNode `xml:"error"`
+	}{nodesOf(e)}, start)
+}
+
+// UnmarshalXML rebuilds Errors from the encoding produced by MarshalXML.
+func (e *Errors) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	var v struct {
+		Errors []errorNode `xml:"error"`
+	}
+	if err := dec.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*e = errorsOf(v.Errors)
+	return nil
+}
+
+// MarshalJSON encodes a StructError as a single object of the array produced
+// by Errors.MarshalJSON.
+func (se StructError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(nodeOf(se))
+}
+
+func (se *StructError) UnmarshalJSON(data []byte) error {
+	var n errorNode
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+
+	*se = StructError{Field: n.Field, Message: n.message()}
+	return nil
+}
+
+// MarshalXML encodes a StructError as a single "error" element of those
+// produced by Errors.MarshalXML.
+func (se StructError) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	return enc.Encode(nodeOf(se))
+}
+
+func (se *StructError) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	var n errorNode
+	if err := dec.DecodeElement(&n, &start); err != nil {
+		return err
+	}
+
+	*se = StructError{Field: n.Field, Message: n.message()}
+	return nil
+}
+
+// MarshalJSON encodes a SliceError as a single object of the array produced
+// by Errors.MarshalJSON.
+func (se SliceError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(nodeOf(se))
+}
+
+func (se *SliceError) UnmarshalJSON(data []byte) error {
+	var n errorNode
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+
+	*se = SliceError{Message: n.message()}
+	if n.Index != nil {
+		se.Index = *n.Index
+	}
+	return nil
+}
+
+// MarshalXML encodes a SliceError as a single "error" element of those
+// produced by Errors.MarshalXML.
+func (se SliceError) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	return enc.Encode(nodeOf(se))
+}
+
+func (se *SliceError) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	var n errorNode
+	if err := dec.DecodeElement(&n, &start); err != nil {
+		return err
+	}
+
+	*se = SliceError{Message: n.message()}
+	if n.Index != nil {
+		se.Index = *n.Index
+	}
+	return nil
+}
+
+// MarshalJSON encodes a MapError as a single object of the array produced by
+// Errors.MarshalJSON.
+func (me MapError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(nodeOf(me))
+}
+
+func (me *MapError) UnmarshalJSON(data []byte) error {
+	var n errorNode
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+
+	*me = MapError{OnKey: n.OnKey, Message: n.message()}
+	if n.Key != nil {
+		me.Key = *n.Key
+	}
+	return nil
+}
+
+// MarshalXML encodes a MapError as a single "error" element of those produced
+// by Errors.MarshalXML.
+func (me MapError) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	return enc.Encode(nodeOf(me))
+}
+
+func (me *MapError) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	var n errorNode
+	if err := dec.DecodeElement(&n, &start); err != nil {
+		return err
+	}
+
+	*me = MapError{OnKey: n.OnKey, Message: n.message()}
+	if n.Key != nil {
+		me.Key = *n.Key
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package legit
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var marshalErrors = Errors{
+	StructError{Field: "users", Message: Errors{
+		SliceError{Index: 0, Message: Errors{
+			StructError{Field: "email", Message: errors.New("invalid email")},
+		}},
+	}},
+	StructError{Field: "tags", Message: Errors{
+		MapError{Key: "FOO", OnKey: true, Message: errors.New("string is not lowercase")},
+	}},
+	ErrTruncated,
+}
+
+const marshalJSON = `[{"field":"users","errors":[{"index":0,"errors":[{"field":"email","message":"invalid email"}]}]},{"field":"tags","errors":[{"key":"FOO","on_key":true,"message":"string is not lowercase"}]},{"truncated":true}]`
+
+const marshalXML = `<errors><error field="users"><error index="0"><error field="email"><message>invalid email</message></error></error></error><error field="tags"><error key="FOO" on_key="true"><message>string is not lowercase</message></error></error><error truncated="true"></error></errors>`
+
+func TestErrors_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(marshalErrors)
+	if assert.NoError(t, err) {
+		assert.Equal(t, marshalJSON, string(b))
+	}
+
+	b, err = json.Marshal(Errors{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, `[]`, string(b))
+	}
+}
+
+func TestErrors_UnmarshalJSON(t *testing.T) {
+	var errs Errors
+	err := json.Unmarshal([]byte(marshalJSON), &errs)
+	if assert.NoError(t, err) {
+		assert.Equal(t, marshalErrors, errs)
+		assert.True(t, errs.Truncated())
+	}
+
+	// a failure with the same message as ErrTruncated is not truncation
+	err = json.Unmarshal([]byte(`[{"message":"too many errors"}]`), &errs)
+	if assert.NoError(t, err) {
+		assert.False(t, errs.Truncated())
+	}
+
+	err = json.Unmarshal([]byte(`{}`), &errs)
+	assert.Error(t, err)
+}
+
+func TestErrors_MarshalXML(t *testing.T) {
+	b, err := xml.Marshal(marshalErrors)
+	if assert.NoError(t, err) {
+		assert.Equal(t, marshalXML, string(b))
+	}
+}
+
+func TestErrors_UnmarshalXML(t *testing.T) {
+	var errs Errors
+	err := xml.Unmarshal([]byte(marshalXML), &errs)
+	if assert.NoError(t, err) {
+		assert.Equal(t, marshalErrors, errs)
+		assert.True(t, errs.Truncated())
+	}
+}
+
+func TestStructError_MarshalJSON(t *testing.T) {
+	se := StructError{Field: "email", Message: errors.New("invalid email")}
+
+	b, err := json.Marshal(se)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"field":"email","message":"invalid email"}`, string(b))
+	}
+
+	var v StructError
+	err = json.Unmarshal(b, &v)
+	if assert.NoError(t, err) {
+		assert.Equal(t, se, v)
+	}
+}
+
+func TestStructError_MarshalXML(t *testing.T) {
+	se := StructError{Field: "email", Message: errors.New("invalid email")}
+
+	b, err := xml.Marshal(se)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `<error field="email"><message>invalid email</message></error>`, string(b))
+	}
+
+	var v StructError
+	err = xml.Unmarshal(b, &v)
+	if assert.NoError(t, err) {
+		assert.Equal(t, se, v)
+	}
+}
+
+func TestSliceError_MarshalJSON(t *testing.T) {
+	se := SliceError{Index: 0, Message: Errors{StructError{Field: "email", Message: errors.New("invalid email")}}}
+
+	b, err := json.Marshal(se)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"index":0,"errors":[{"field":"email","message":"invalid email"}]}`, string(b))
+	}
+
+	var v SliceError
+	err = json.Unmarshal(b, &v)
+	if assert.NoError(t, err) {
+		assert.Equal(t, se, v)
+	}
+}
+
+func TestSliceError_MarshalXML(t *testing.T) {
+	se := SliceError{Index: 2, Message: errors.New("invalid email")}
+
+	b, err := xml.Marshal(se)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `<error index="2"><message>invalid email</message></error>`, string(b))
+	}
+
+	var v SliceError
+	err = xml.Unmarshal(b, &v)
+	if assert.NoError(t, err) {
+		assert.Equal(t, se, v)
+	}
+}
+
+func TestMapError_MarshalJSON(t *testing.T) {
+	me := MapError{Key: "foo", Message: errors.New("invalid email")}
+
+	b, err := json.Marshal(me)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"key":"foo","message":"invalid email"}`, string(b))
+	}
+
+	var v MapError
+	err = json.Unmarshal(b, &v)
+	if assert.NoError(t, err) {
+		assert.Equal(t, me, v)
+	}
+}
+
+func TestMapError_MarshalXML(t *testing.T) {
+	me := MapError{Key: "foo", OnKey: true, Message: errors.New("invalid email")}
+
+	b, err := xml.Marshal(me)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `<error key="foo" on_key="true"><message>invalid email</message></error>`, string(b))
+	}
+
+	var v MapError
+	err = xml.Unmarshal(b, &v)
+	if assert.NoError(t, err) {
+		assert.Equal(t, me, v)
+	}
+}
+
+func TestErrors_MarshalJSON_validation(t *testing.T) {
+	err := Validate(struct {
+		Email Email `json:"email"`
+	}{"foo"})
+
+	b, jerr := json.Marshal(err)
+	if assert.NoError(t, jerr) {
+		assert.Equal(t, `[{"field":"Email","code":"email.invalid","message":"invalid email"}]`, string(b))
+	}
+}
+
+func TestErrors_MarshalJSON_validationError(t *testing.T) {
+	errs := Errors{StructError{Field: "age", Message: NewValidationError("number.min", "must be at least {min}", map[string]interface{}{"min": 18.0})}}
+
+	b, err := json.Marshal(errs)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `[{"field":"age","code":"number.min","message":"must be at least 18","params":{"min":18}}]`, string(b))
+	}
+
+	var v Errors
+	err = json.Unmarshal(b, &v)
+	if assert.NoError(t, err) {
+		assert.Equal(t, Errors{StructError{Field: "age", Message: NewValidationError("number.min", "must be at least 18", map[string]interface{}{"min": 18.0})}}, v)
+		assert.True(t, errors.Is(v, &ValidationError{Code: "number.min"}))
+	}
+}
+
+func TestErrors_MarshalXML_validationError(t *testing.T) {
+	errs := Errors{StructError{Field: "age", Message: NewValidationError("number.range", "must be between {min} and {max}", map[string]interface{}{"min": 18, "max": 65})}}
+
+	b, err := xml.Marshal(errs)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `<errors><error field="age" code="number.range"><message>must be between 18 and 65</message><param name="max">65</param><param name="min">18</param></error></errors>`, string(b))
+	}
+
+	var v Errors
+	err = xml.Unmarshal(b, &v)
+	if assert.NoError(t, err) {
+		assert.Equal(t, Errors{StructError{Field: "age", Message: NewValidationError("number.range", "must be between 18 and 65", map[string]interface{}{"min": "18", "max": "65"})}}, v)
+	}
+}
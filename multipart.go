@@ -0,0 +1,135 @@
+package legit
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+)
+
+// ErrBoundary is returned when a multipart body is decoded without the
+// boundary parameter of its MIME type.
+var ErrBoundary = errors.New("multipart boundary not given")
+
+// DefaultMaxMemory is the memory used to store multipart bodies before file
+// parts are spilled to temporary files, when not configured by Multipart or
+// Form.
+const DefaultMaxMemory = 32 << 20
+
+// Multipart decoder can decode any multipart/form-data body. Text parts are
+// bound to struct fields in the same way as the URLEncoded decoder, and file
+// parts to fields of the type File, *File, []File or types embedding File.
+//
+// When decoding a request with ParseRequestAndValidate, temporary files are
+// removed by net/http once the handler returns. As nothing would remove them
+// when decoding a reader, the body is instead held in memory and a LimitError
+// is returned if it is larger than MaxMemory.
+type Multipart struct {
+	// MaxMemory is the memory used to store a body before file parts are
+	// spilled to temporary files, DefaultMaxMemory is used when zero. Bodies
+	// decoded from a reader may not be larger.
+	MaxMemory int64
+}
+
+func (m Multipart) Match(mime string) bool {
+	return isMediaType(mime, "multipart/form-data")
+}
+
+// Decode returns ErrBoundary, multipart bodies require the boundary parameter
+// given to DecodeParams
+func (m Multipart) Decode(r io.Reader, dst interface{}) error {
+	return ErrBoundary
+}
+
+func (m Multipart) DecodeParams(r io.Reader, params map[string]string, dst interface{}) error {
+	boundary := params["boundary"]
+	if boundary == "" {
+		return ErrBoundary
+	}
+
+	max := m.maxMemory()
+	b, err := io.ReadAll(io.LimitReader(r, max+1))
+	if err != nil {
+		return err
+	} else if int64(len(b)) > max {
+		return &LimitError{Err: ErrBodyTooLarge, Max: max}
+	}
+
+	// file parts are only spilled to temporary files once their total size
+	// exceeds max, which a body of at most max bytes cannot
+	form, err := multipart.NewReader(bytes.NewReader(b), boundary).ReadForm(max)
+	if err != nil {
+		return err
+	}
+
+	return bindForm(form.Value, form.File, dst)
+}
+
+func (m Multipart) DecodeRequest(r *http.Request, dst interface{}) error {
+	err := r.ParseMultipartForm(m.maxMemory())
+	if err == http.ErrMissingBoundary {
+		return ErrBoundary
+	} else if err != nil {
+		return err
+	}
+
+	return bindForm(r.MultipartForm.Value, r.MultipartForm.File, dst)
+}
+
+func (m Multipart) TagName() string {
+	return "form"
+}
+
+func (m Multipart) maxMemory() int64 {
+	if m.MaxMemory > 0 {
+		return m.MaxMemory
+	}
+
+	return DefaultMaxMemory
+}
+
+var fileType = reflect.TypeOf(File{})
+
+// returns the File of a value if it is a File or embeds one
+func fileOf(v reflect.Value) (reflect.Value, bool) {
+	if v.Type() == fileType {
+		return v, true
+	}
+
+	if v.Kind() == reflect.Struct {
+		for i := 0; i < v.NumField(); i++ {
+			if ft := v.Type().Field(i); ft.Anonymous && ft.Type == fileType {
+				return v.Field(i), true
+			}
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// returns true if values of a type are bound to uploaded files
+func isFileType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	_, ok := fileOf(reflect.New(t).Elem())
+	return ok
+}
+
+// bind the first uploaded file of a node to a File
+func bindFile(v reflect.Value, n *formNode) error {
+	if len(n.files) < 1 {
+		return nil
+	}
+
+	f, err := newFile(n.files[0])
+	if err != nil {
+		return err
+	}
+
+	v.Set(reflect.ValueOf(f))
+	return nil
+}
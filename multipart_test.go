@@ -0,0 +1,186 @@
+package legit
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultipart_Match(t *testing.T) {
+	m := Multipart{}
+	assert.True(t, m.Match("multipart/form-data"))
+	assert.True(t, m.Match("multipart/form-data; boundary=foo"))
+	assert.False(t, m.Match("application/json"))
+	assert.True(t, m.Match("Multipart/Form-Data; boundary=foo"))
+	assert.False(t, m.Match("multipart/mixed; boundary=foo"))
+}
+
+func TestMultipart_TagName(t *testing.T) {
+	assert.Equal(t, "form", Multipart{}.TagName())
+}
+
+var pngHeader = []byte("\x89PNG\x0D\x0A\x1A\x0A")
+
+type avatar struct {
+	File
+}
+
+func (a avatar) Validate() error {
+	return FileLimits{Types: []string{"image/png"}}.Validate(a.File)
+}
+
+type multipartUser struct {
+	Name   Alpha   `form:"name"`
+	Tags   []Lower `form:"tags"`
+	Avatar avatar  `form:"avatar"`
+	Resume *File   `form:"resume"`
+	Photos []File  `form:"photos"`
+	Empty  *File   `form:"empty"`
+}
+
+// returns a multipart body and its content type
+func multipartBody(t *testing.T, fn func(w *multipart.Writer)) (*bytes.Buffer, string) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	fn(w)
+	if !assert.NoError(t, w.Close()) {
+		t.FailNow()
+	}
+
+	return &buf, w.FormDataContentType()
+}
+
+func writeFile(w *multipart.Writer, field, name string, content []byte) {
+	fw, _ := w.CreateFormFile(field, name)
+	fw.Write(content)
+}
+
+func TestMultipart_DecodeParams(t *testing.T) {
+	body, ct := multipartBody(t, func(w *multipart.Writer) {
+		w.WriteField("name", "foo")
+		w.WriteField("tags", "a")
+		w.WriteField("tags", "b")
+		writeFile(w, "avatar", "a.png", pngHeader)
+		writeFile(w, "resume", "cv.txt", []byte("hello world"))
+		writeFile(w, "photos", "1.png", pngHeader)
+		writeFile(w, "photos", "2.png", pngHeader)
+	})
+
+	var u multipartUser
+	err := form.ParseAndValidate(body, ct, &u)
+	assert.EqualError(t, err, ErrEncoding.Error())
+
+	f := NewForm()
+	f.Decoders = Decoders{Multipart{}}
+	if !assert.NoError(t, f.ParseAndValidate(body, ct, &u)) {
+		return
+	}
+
+	assert.Equal(t, Alpha("foo"), u.Name)
+	assert.Equal(t, []Lower{"a", "b"}, u.Tags)
+	assert.Equal(t, "a.png", u.Avatar.Filename)
+	assert.Equal(t, "image/png", u.Avatar.ContentType)
+	assert.Equal(t, int64(len(pngHeader)), u.Avatar.Size)
+	if assert.NotNil(t, u.Resume) {
+		assert.Equal(t, "cv.txt", u.Resume.Filename)
+		assert.Equal(t, "text/plain; charset=utf-8", u.Resume.ContentType)
+
+		r, err := u.Resume.Open()
+		if assert.NoError(t, err) {
+			b, _ := ioutil.ReadAll(r)
+			r.Close()
+			assert.Equal(t, "hello world", string(b))
+		}
+	}
+	if assert.Len(t, u.Photos, 2) {
+		assert.Equal(t, "1.png", u.Photos[0].Filename)
+		assert.Equal(t, "2.png", u.Photos[1].Filename)
+	}
+	assert.Nil(t, u.Empty)
+}
+
+func TestMultipart_DecodeParams_invalid(t *testing.T) {
+	f := NewForm()
+	f.Decoders = Decoders{Multipart{}}
+
+	body, ct := multipartBody(t, func(w *multipart.Writer) {
+		writeFile(w, "avatar", "a.png", []byte("not an image"))
+	})
+
+	var u multipartUser
+	err := f.ParseAndValidate(body, ct, &u)
+	assert.Equal(t, Errors{
+		StructError{Field: "avatar", Message: NewValidationError("file.type_not_allowed", "file type {type} is not allowed", map[string]interface{}{"type": "text/plain"})},
+	}, err)
+
+	err = f.ParseAndValidate(body, "multipart/form-data", &u)
+	assert.Equal(t, &DecodeError{Err: ErrBoundary}, err)
+}
+
+func TestMultipart_DecodeParams_maxMemory(t *testing.T) {
+	body, ct := multipartBody(t, func(w *multipart.Writer) {
+		writeFile(w, "avatar", "a.png", pngHeader)
+	})
+	params := map[string]string{"boundary": ct[len("multipart/form-data; boundary="):]}
+
+	// bodies read from a reader are never spilled to temporary files
+	var u multipartUser
+	m := Multipart{MaxMemory: int64(body.Len()) - 1}
+	err := m.DecodeParams(bytes.NewReader(body.Bytes()), params, &u)
+	assert.Equal(t, &LimitError{Err: ErrBodyTooLarge, Max: m.MaxMemory}, err)
+
+	m.MaxMemory = int64(body.Len())
+	if assert.NoError(t, m.DecodeParams(bytes.NewReader(body.Bytes()), params, &u)) {
+		assert.Equal(t, "image/png", u.Avatar.ContentType)
+	}
+
+	f := NewForm()
+	f.Decoders = Decoders{Multipart{}}
+	f.MaxMemory = 1
+	assert.True(t, errors.Is(f.ParseAndValidate(body, ct, &u), ErrBodyTooLarge))
+}
+
+func TestMultipart_DecodeRequest(t *testing.T) {
+	body, ct := multipartBody(t, func(w *multipart.Writer) {
+		w.WriteField("name", "foo")
+		writeFile(w, "avatar", "a.png", pngHeader)
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/", body)
+	r.Header.Set("Content-Type", ct)
+
+	f := NewForm()
+	f.Decoders = Decoders{Multipart{}}
+	f.MaxMemory = 1
+
+	var u multipartUser
+	if assert.NoError(t, f.ParseRequestAndValidate(r, &u)) {
+		assert.Equal(t, Alpha("foo"), u.Name)
+		assert.Equal(t, "image/png", u.Avatar.ContentType)
+		assert.NotNil(t, r.MultipartForm)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/", body)
+	r.Header.Set("Content-Type", "multipart/form-data")
+	assert.Equal(t, &DecodeError{Err: ErrBoundary}, f.ParseRequestAndValidate(r, &u))
+}
+
+func TestMultipart_Decode(t *testing.T) {
+	assert.Equal(t, ErrBoundary, Multipart{}.Decode(nil, nil))
+}
+
+func TestForm_decoder(t *testing.T) {
+	f := NewForm()
+	f.Decoders = Decoders{Multipart{MaxMemory: 10}}
+	assert.Equal(t, Multipart{MaxMemory: 10}, f.decoder("multipart/form-data"))
+
+	f.MaxMemory = 20
+	assert.Equal(t, Multipart{MaxMemory: 20}, f.decoder("multipart/form-data"))
+	assert.Nil(t, f.decoder("application/json"))
+}
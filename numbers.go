@@ -1,17 +1,14 @@
 package legit
 
-import (
-	"errors"
-)
-
 // Positive validates any integer that contains a value above (and including) zero.
 type Positive int
 
-var errPositive = errors.New("number is not positive")
+// ErrPositive is returned when a Positive is below zero.
+var ErrPositive = NewValidationError("number.not_positive", "number is not positive", nil)
 
 func (p Positive) Validate() error {
 	if p < 0 {
-		return errPositive
+		return ErrPositive
 	}
 
 	return nil
@@ -20,11 +17,12 @@ func (p Positive) Validate() error {
 // Negative validates any integer that contains a value below zero.
 type Negative int
 
-var errNegative = errors.New("number is not negative")
+// ErrNegative is returned when a Negative is not below zero.
+var ErrNegative = NewValidationError("number.not_negative", "number is not negative", nil)
 
 func (n Negative) Validate() error {
 	if n > -1 {
-		return errNegative
+		return ErrNegative
 	}
 
 	return nil
@@ -7,11 +7,11 @@ import (
 )
 
 func TestPositive(t *testing.T) {
-	testNumber(t, Positive(100), Positive(-100), errPositive)
+	testNumber(t, Positive(100), Positive(-100), ErrPositive)
 }
 
 func TestNegative(t *testing.T) {
-	testNumber(t, Negative(-100), Negative(100), errNegative)
+	testNumber(t, Negative(-100), Negative(100), ErrNegative)
 }
 
 func testNumber(t *testing.T, pass, fail Validator, failErr error) {
@@ -1,7 +1,6 @@
 package legit
 
 import (
-	"errors"
 	"regexp"
 )
 
@@ -11,11 +10,12 @@ type Email string
 // RFC 5322 "official" email regexp
 var expEmail = regexp.MustCompile(`(?:[a-z0-9!#$%&'*+/=?^_{|}~-]+(?:\.[a-z0-9!#$%&'*+/=?^_{|}~-]+)*|"(?:[\x01-\x08\x0b\x0c\x0e-\x1f\x21\x23-\x5b\x5d-\x7f]|\\[\x01-\x09\x0b\x0c\x0e-\x7f])*")@(?:(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z0-9](?:[a-z0-9-]*[a-z0-9])?|\[(?:(?:25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?)\.){3}(?:25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?|[a-z0-9-]*[a-z0-9]:(?:[\x01-\x08\x0b\x0c\x0e-\x1f\x21-\x5a\x53-\x7f]|\\[\x01-\x09\x0b\x0c\x0e-\x7f])+)\])`)
 
-var errEmail = errors.New("invalid email")
+// ErrEmail is returned when an Email is not a valid email address.
+var ErrEmail = NewValidationError("email.invalid", "invalid email", nil)
 
 func (e Email) Validate() error {
 	if !expEmail.MatchString(string(e)) {
-		return errEmail
+		return ErrEmail
 	}
 
 	return nil
@@ -26,11 +26,13 @@ type CreditCard string
 
 var expCreditCard = regexp.MustCompile(`^(?:4[0-9]{12}(?:[0-9]{3})?|5[1-5][0-9]{14}|6(?:011|5[0-9][0-9])[0-9]{12}|3[47][0-9]{13}|3(?:0[0-5]|[68][0-9])[0-9]{11}|(?:2131|1800|35\d{3})\d{11})$`)
 
-var errCreditCard = errors.New("invalid credit card")
+// ErrCreditCard is returned when a CreditCard is not a valid credit card
+// number.
+var ErrCreditCard = NewValidationError("credit_card.invalid", "invalid credit card", nil)
 
 func (c CreditCard) Validate() error {
 	if !expCreditCard.MatchString(string(c)) {
-		return errCreditCard
+		return ErrCreditCard
 	}
 
 	return nil
@@ -41,11 +43,12 @@ type UUID string
 
 var expUUID = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
 
-var errUUID = errors.New("invalid uuid")
+// ErrUUID is returned when a UUID is not a valid UUID.
+var ErrUUID = NewValidationError("uuid.invalid", "invalid uuid", nil)
 
 func (u UUID) Validate() error {
 	if !expUUID.MatchString(string(u)) {
-		return errUUID
+		return ErrUUID
 	}
 
 	return nil
@@ -56,11 +59,12 @@ type UUID3 string
 
 var expUUID3 = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-3[0-9a-f]{3}-[0-9a-f]{4}-[0-9a-f]{12}$`)
 
-var errUUID3 = errors.New("invalid uuid3")
+// ErrUUID3 is returned when a UUID3 is not a valid version 3 UUID.
+var ErrUUID3 = NewValidationError("uuid3.invalid", "invalid uuid3", nil)
 
 func (u UUID3) Validate() error {
 	if !expUUID3.MatchString(string(u)) {
-		return errUUID3
+		return ErrUUID3
 	}
 
 	return nil
@@ -71,11 +75,12 @@ type UUID4 string
 
 var expUUID4 = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
 
-var errUUID4 = errors.New("invalid uuid4")
+// ErrUUID4 is returned when a UUID4 is not a valid version 4 UUID.
+var ErrUUID4 = NewValidationError("uuid4.invalid", "invalid uuid4", nil)
 
 func (u UUID4) Validate() error {
 	if !expUUID4.MatchString(string(u)) {
-		return errUUID4
+		return ErrUUID4
 	}
 
 	return nil
@@ -86,11 +91,12 @@ type UUID5 string
 
 var expUUID5 = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
 
-var errUUID5 = errors.New("invalid uuid5")
+// ErrUUID5 is returned when a UUID5 is not a valid version 5 UUID.
+var ErrUUID5 = NewValidationError("uuid5.invalid", "invalid uuid5", nil)
 
 func (u UUID5) Validate() error {
 	if !expUUID5.MatchString(string(u)) {
-		return errUUID5
+		return ErrUUID5
 	}
 
 	return nil
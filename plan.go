@@ -0,0 +1,154 @@
+package legit
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// plan is the compiled validation strategy for a type, built once and cached
+// so later validations avoid repeating reflection
+type plan struct {
+	kind reflect.Kind
+
+	// type implements Validator or ValidatorContext
+	validator bool
+
+	// only a pointer to the type implements Validator or ValidatorContext
+	ptrValidator bool
+
+	// elements of a slice, array or map may contain errors without strict
+	// mode
+	visitElem bool
+
+	// fields to visit when the type is a struct
+	fields []fieldPlan
+}
+
+// fieldPlan describes a struct field to be visited during validation
+type fieldPlan struct {
+	index int
+	name  string
+
+	// field is an embedded struct, or pointer to a struct, whose fields are
+	// promoted into the outer struct
+	embedded bool
+
+	// field may contain errors without strict mode, it is a validator or a
+	// container of validators
+	visit bool
+}
+
+var plans sync.Map // map[planKey]*plan
+
+// plans are compiled for each struct tag used to name fields
+type planKey struct {
+	typ reflect.Type
+	tag string
+}
+
+// planOf returns the compiled plan for a type, with fields named by the given
+// struct tag, compiling and caching it on first use
+func planOf(t reflect.Type, tag string) *plan {
+	k := planKey{typ: t, tag: tag}
+	if p, ok := plans.Load(k); ok {
+		return p.(*plan)
+	}
+
+	p, _ := plans.LoadOrStore(k, compilePlan(t, tag))
+	return p.(*plan)
+}
+
+func compilePlan(t reflect.Type, tag string) *plan {
+	p := &plan{
+		kind:      t.Kind(),
+		validator: isValidator(t),
+	}
+
+	if !p.validator && t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface {
+		p.ptrValidator = isValidator(reflect.PtrTo(t))
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		p.visitElem = mayVisit(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			ft := t.Field(i)
+
+			name, tagged, skip := fieldName(ft, tag)
+			if skip {
+				continue
+			}
+
+			// embedded structs have their fields promoted, they are planned
+			// separately when visited so recursive types are supported. as
+			// with encoding/json, embedded structs given a name by their tag
+			// are treated as a named field.
+			if ft.Anonymous && !tagged && isEmbeddedStruct(ft.Type) {
+				p.fields = append(p.fields, fieldPlan{index: i, name: name, embedded: true, visit: true})
+				continue
+			}
+
+			// see reflect StructField.PkgPath for determining if field is exported
+			// TODO: is there a better way to determine if a field is exported?
+			if len(ft.PkgPath) < 1 {
+				p.fields = append(p.fields, fieldPlan{index: i, name: name, visit: mayVisit(ft.Type)})
+			}
+		}
+	}
+
+	return p
+}
+
+// returns true if validating a value of a type may return an error outside
+// of strict mode
+func mayVisit(t reflect.Type) bool {
+	if isValidator(t) || isValidator(reflect.PtrTo(t)) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map, reflect.Ptr, reflect.Interface:
+		return true
+	}
+
+	return false
+}
+
+// returns the name of a field from a struct tag, such as json or xml, falling
+// back to the Go field name when the tag is empty or does not name the field.
+// tagged is set when the tag names the field, skip is set when the tag
+// excludes the field with "-".
+func fieldName(ft reflect.StructField, tag string) (name string, tagged, skip bool) {
+	if tag == "" {
+		return ft.Name, false, false
+	}
+
+	v := ft.Tag.Get(tag)
+	if v == "-" {
+		return "", false, true
+	}
+
+	// discard options such as omitempty
+	if i := strings.Index(v, ","); i > -1 {
+		v = v[:i]
+	}
+
+	if tag == "xml" {
+		// discard the namespace of "namespace-URL name", and parent elements
+		// of "a>b>c"
+		if i := strings.LastIndex(v, " "); i > -1 {
+			v = v[i+1:]
+		}
+		if i := strings.LastIndex(v, ">"); i > -1 {
+			v = v[i+1:]
+		}
+	}
+
+	if v == "" {
+		return ft.Name, false, false
+	}
+
+	return v, true, false
+}
@@ -0,0 +1,177 @@
+package legit
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanOf(t *testing.T) {
+	p := planOf(reflect.TypeOf(struct {
+		embeddedName
+		Email   Email
+		Name    pointerName
+		Tags    []Lower
+		Comment string
+		comment Lower
+	}{}), "")
+
+	assert.Equal(t, reflect.Struct, p.kind)
+	assert.False(t, p.validator)
+	assert.Equal(t, []fieldPlan{
+		{index: 0, name: "embeddedName", embedded: true, visit: true},
+		{index: 1, name: "Email", visit: true},
+		{index: 2, name: "Name", visit: true},
+		{index: 3, name: "Tags", visit: true},
+		{index: 4, name: "Comment", visit: false},
+	}, p.fields)
+}
+
+func TestPlanOf_validator(t *testing.T) {
+	p := planOf(reflect.TypeOf(Lower("")), "")
+	assert.True(t, p.validator)
+	assert.False(t, p.ptrValidator)
+
+	p = planOf(reflect.TypeOf(pointerName("")), "")
+	assert.False(t, p.validator)
+	assert.True(t, p.ptrValidator)
+
+	p = planOf(reflect.TypeOf([]string{}), "")
+	assert.False(t, p.visitElem)
+
+	p = planOf(reflect.TypeOf(map[string]Lower{}), "")
+	assert.True(t, p.visitElem)
+}
+
+func TestPlanOf_tagName(t *testing.T) {
+	p := planOf(reflect.TypeOf(struct {
+		embeddedName
+		*EmbeddedEmail `json:"contact"`
+		First          Lower `json:"first_name,omitempty"`
+		Last           Lower `json:",omitempty"`
+		Secret         Lower `json:"-"`
+		Dash           Lower `json:"-,"`
+	}{}), "json")
+
+	assert.Equal(t, []fieldPlan{
+		{index: 0, name: "embeddedName", embedded: true, visit: true},
+		{index: 1, name: "contact", visit: true},
+		{index: 2, name: "first_name", visit: true},
+		{index: 3, name: "Last", visit: true},
+		{index: 5, name: "-", visit: true},
+	}, p.fields)
+}
+
+func TestFieldName(t *testing.T) {
+	typ := reflect.TypeOf(struct {
+		A string `json:"a,omitempty" xml:"urn:foo b,attr"`
+		B string `xml:"parent>child"`
+		C string `json:"-"`
+	}{})
+
+	tests := []struct {
+		Field  int
+		Tag    string
+		Name   string
+		Tagged bool
+		Skip   bool
+	}{
+		{0, "", "A", false, false},
+		{0, "json", "a", true, false},
+		{0, "xml", "b", true, false},
+		{1, "json", "B", false, false},
+		{1, "xml", "child", true, false},
+		{2, "json", "", false, true},
+	}
+
+	for _, test := range tests {
+		name, tagged, skip := fieldName(typ.Field(test.Field), test.Tag)
+		assert.Equal(t, test.Name, name)
+		assert.Equal(t, test.Tagged, tagged)
+		assert.Equal(t, test.Skip, skip)
+	}
+}
+
+func TestPlanOf_cached(t *testing.T) {
+	typ := reflect.TypeOf(struct{ Name Lower }{})
+
+	var wg sync.WaitGroup
+	ps := make([]*plan, 8)
+	for i := range ps {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ps[i] = planOf(typ, "")
+		}(i)
+	}
+	wg.Wait()
+
+	for _, p := range ps {
+		assert.True(t, ps[0] == p)
+	}
+}
+
+func TestLegit_validate_strictPlain(t *testing.T) {
+	l := Legit{Strict: true}
+	err := l.validate(reflected(struct {
+		Name    Lower
+		Comment string
+	}{"foo", "bar"}))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{StructError{Field: "Comment", Message: ErrStrict}}, err)
+	}
+
+	err = l.validate(reflected([]string{"foo"}))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{SliceError{Index: 0, Message: ErrStrict}}, err)
+	}
+}
+
+type benchAddress struct {
+	Street Printable
+	City   Alpha
+	Zip    Number
+	Note   string
+}
+
+type benchUser struct {
+	ID        UUID4
+	Email     Email
+	Name      Printable
+	Age       Positive
+	Tags      []Lower
+	Addresses []benchAddress
+	Comment   string
+	Count     int
+}
+
+var benchUserValue = benchUser{
+	ID:        "625e63f3-58f5-40b7-83a1-a72ad31acffb",
+	Email:     "foo@example.org",
+	Name:      "Foo Bar",
+	Age:       30,
+	Tags:      []Lower{"foo", "bar", "baz"},
+	Addresses: []benchAddress{{Street: "1 Foo Street", City: "London", Zip: "12345"}, {Street: "2 Bar Street", City: "Paris", Zip: "67890"}},
+}
+
+func BenchmarkValidate_struct(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Validate(&benchUserValue)
+	}
+}
+
+func BenchmarkValidate_slice(b *testing.B) {
+	users := make([]benchUser, 100)
+	for i := range users {
+		users[i] = benchUserValue
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Validate(users)
+	}
+}
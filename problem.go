@@ -0,0 +1,212 @@
+package legit
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Problem is an RFC 7807 (RFC 9457) problem details document describing why
+// a request could not be decoded or validated. Validation failures are listed
+// in the "errors" extension member, and the "truncated" extension member is set
+// when validation stopped at Legit.MaxErrors before finding every failure.
+type Problem struct {
+	XMLName   xml.Name      `json:"-" xml:"urn:ietf:rfc:7807 problem"`
+	Type      string        `json:"type,omitempty" xml:"type,omitempty"`
+	Title     string        `json:"title" xml:"title"`
+	Status    int           `json:"status" xml:"status"`
+	Detail    string        `json:"detail,omitempty" xml:"detail,omitempty"`
+	Instance  string        `json:"instance,omitempty" xml:"instance,omitempty"`
+	Errors    ProblemErrors `json:"errors,omitempty" xml:"errors,omitempty"`
+	Truncated bool          `json:"truncated,omitempty" xml:"truncated,omitempty"`
+}
+
+// ProblemErrors lists the validation failures of a Problem. RFC 7807 encodes
+// arrays in XML as "i" elements.
+type ProblemErrors []ProblemError
+
+func (pe ProblemErrors) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	return enc.EncodeElement(struct {
+		Items []ProblemError `xml:"i"`
+	}{pe}, start)
+}
+
+func (pe *ProblemErrors) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	var v struct {
+		Items []ProblemError `xml:"i"`
+	}
+	if err := dec.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	*pe = v.Items
+	return nil
+}
+
+// ProblemError is a single validation failure within a Problem, identified by
+// both a JSON Pointer and dotted path to the value which failed
+type ProblemError struct {
+	Pointer string `json:"pointer" xml:"pointer"`
+	Field   string `json:"field" xml:"field"`
+	Code    string `json:"code,omitempty" xml:"code,omitempty"`
+	Message string `json:"message" xml:"message"`
+}
+
+// NewProblem returns the Problem describing an error returned by Form. An
+// unknown encoding, charset or content encoding is reported with the status
+// 415, a body larger than Form.MaxBodyBytes with 413, decoding failures with
+// 400 and validation failures with 422. Decoding failures located within the
+// body are listed in the same way as validation failures, which Form always
+// returns as Errors or a SummaryError. Any other error is reported with the status 500 without
+// its message, which may be internal.
+func NewProblem(err error) Problem {
+	var (
+		de  *DecodeError
+		uce *UnsupportedCharsetError
+		uee *UnsupportedEncodingError
+	)
+
+	switch {
+	case errors.Is(err, ErrEncoding), errors.As(err, &uce), errors.As(err, &uee):
+		return newProblem(http.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, ErrBodyTooLarge):
+		return newProblem(http.StatusRequestEntityTooLarge, err.Error())
+	case errors.As(err, &de):
+		if len(de.Errors) < 1 {
+			return newProblem(http.StatusBadRequest, err.Error())
+		}
+
+		p := newProblem(http.StatusBadRequest, "decoding failed")
+		p.Errors = problemErrors(de.Errors)
+		return p
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return newProblem(http.StatusServiceUnavailable, err.Error())
+	case !isValidationError(err):
+		return newProblem(http.StatusInternalServerError, "internal error")
+	}
+
+	p := newProblem(http.StatusUnprocessableEntity, "validation failed")
+	p.Errors = problemErrors(err)
+
+	var te interface{ Truncated() bool }
+	p.Truncated = errors.As(err, &te) && te.Truncated()
+	return p
+}
+
+// returns true if err contains the failures of a validator
+func isValidationError(err error) bool {
+	var (
+		errs Errors
+		sme  SummaryError
+		se   StructError
+		sle  SliceError
+		me   MapError
+		fe   FieldError
+		ve   *ValidationError
+	)
+
+	return errors.As(err, &errs) || errors.As(err, &sme) || errors.As(err, &se) ||
+		errors.As(err, &sle) || errors.As(err, &me) || errors.As(err, &fe) ||
+		errors.As(err, &ve)
+}
+
+// returns every leaf failure within err as a ProblemError
+func problemErrors(err error) ProblemErrors {
+	var pes ProblemErrors
+
+	for _, fe := range Flatten(err) {
+		pe := ProblemError{
+			Pointer: fe.Path.Pointer(),
+			Field:   fe.Path.String(),
+			Message: fe.Message.Error(),
+		}
+
+		var ve *ValidationError
+		if errors.As(fe.Message, &ve) {
+			pe.Code = ve.Code
+		}
+
+		pes = append(pes, pe)
+	}
+
+	return pes
+}
+
+func newProblem(status int, detail string) Problem {
+	return Problem{
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// WriteProblem writes the Problem describing an error returned by Form as the
+// response to a request. The document is encoded as "application/problem+xml"
+// if preferred by the "Accept" header of the request, otherwise as
+// "application/problem+json".
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) error {
+	p := NewProblem(err)
+
+	// advertise the content encodings which can be decoded, RFC 9110 15.5.16
+	var uee *UnsupportedEncodingError
+	if errors.As(err, &uee) {
+		w.Header().Set("Accept-Encoding", "gzip, deflate")
+	}
+
+	if acceptsXML(r.Header.Get("Accept")) {
+		w.Header().Set("Content-Type", "application/problem+xml")
+		w.WriteHeader(p.Status)
+
+		if _, err := w.Write([]byte(xml.Header)); err != nil {
+			return err
+		}
+		return xml.NewEncoder(w).Encode(p)
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+
+	return json.NewEncoder(w).Encode(p)
+}
+
+// returns true if an Accept header prefers an XML media type over JSON
+func acceptsXML(header string) bool {
+	type pref struct {
+		xml bool
+		q   float64
+	}
+
+	var prefs []pref
+	for _, part := range strings.Split(header, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+
+		switch {
+		case isXML(mt):
+			prefs = append(prefs, pref{true, q})
+		case isJSON(mt), mt == "*/*", mt == "application/*":
+			prefs = append(prefs, pref{false, q})
+		}
+	}
+
+	sort.SliceStable(prefs, func(i, j int) bool {
+		return prefs[i].q > prefs[j].q
+	})
+
+	return len(prefs) > 0 && prefs[0].xml && prefs[0].q > 0
+}
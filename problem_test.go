@@ -0,0 +1,170 @@
+package legit
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProblem(t *testing.T) {
+	p := NewProblem(ErrEncoding)
+	assert.Equal(t, Problem{Title: "Unsupported Media Type", Status: 415, Detail: "unknown encoding"}, p)
+
+	p = NewProblem(&DecodeError{Err: errors.New("unexpected EOF")})
+	assert.Equal(t, Problem{Title: "Bad Request", Status: 400, Detail: "unexpected EOF"}, p)
+
+	p = NewProblem(newDecodeError(&json.UnmarshalTypeError{Value: "string", Type: reflect.TypeOf(0), Offset: 12, Field: "users.email"}))
+	assert.Equal(t, Problem{
+		Title:  "Bad Request",
+		Status: 400,
+		Detail: "decoding failed",
+		Errors: ProblemErrors{
+			{Pointer: "/users/email", Field: "users.email", Code: "decode.type", Message: "expected number"},
+		},
+	}, p)
+
+	p = NewProblem(context.DeadlineExceeded)
+	assert.Equal(t, 503, p.Status)
+
+	p = NewProblem(Errors{
+		StructError{Field: "users", Message: Errors{SliceError{Index: 3, Message: Errors{StructError{Field: "email", Message: ErrEmail}}}}},
+		StructError{Field: "name", Message: errors.New("invalid name")},
+	})
+	assert.Equal(t, Problem{
+		Title:  "Unprocessable Entity",
+		Status: 422,
+		Detail: "validation failed",
+		Errors: ProblemErrors{
+			{Pointer: "/users/3/email", Field: "users[3].email", Code: "email.invalid", Message: "invalid email"},
+			{Pointer: "/name", Field: "name", Message: "invalid name"},
+		},
+	}, p)
+
+	p = NewProblem(ErrLower)
+	assert.Equal(t, 422, p.Status)
+	assert.Equal(t, ProblemErrors{{Pointer: "", Field: "", Code: "string.not_lowercase", Message: "string is not lowercase"}}, p.Errors)
+
+	p = NewProblem(fmt.Errorf("field %q: %w", "email", ErrEmail))
+	assert.Equal(t, 422, p.Status)
+
+	p = NewProblem(Errors{StructError{Field: "email", Message: ErrEmail}, ErrTruncated})
+	assert.Equal(t, Problem{
+		Title:     "Unprocessable Entity",
+		Status:    422,
+		Detail:    "validation failed",
+		Errors:    ProblemErrors{{Pointer: "/email", Field: "email", Code: "email.invalid", Message: "invalid email"}},
+		Truncated: true,
+	}, p)
+
+	p = NewProblem(SummaryError{Errors{StructError{Field: "email", Message: ErrEmail}, ErrTruncated}})
+	assert.Equal(t, 422, p.Status)
+	assert.Len(t, p.Errors, 1)
+	assert.True(t, p.Truncated)
+
+	p = NewProblem(errors.New("pq: connection refused"))
+	assert.Equal(t, Problem{Title: "Internal Server Error", Status: 500, Detail: "internal error"}, p)
+}
+
+func TestWriteProblem(t *testing.T) {
+	r := httptest.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+
+	err := WriteProblem(w, r, Errors{StructError{Field: "email", Message: ErrEmail}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 422, w.Code)
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"title":"Unprocessable Entity","status":422,"detail":"validation failed","errors":[{"pointer":"/email","field":"email","code":"email.invalid","message":"invalid email"}]}`, w.Body.String())
+
+		var p Problem
+		if assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &p)) {
+			assert.Equal(t, NewProblem(Errors{StructError{Field: "email", Message: ErrEmail}}), p)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	err = WriteProblem(w, r, Errors{StructError{Field: "email", Message: ErrEmail}, ErrTruncated})
+	if assert.NoError(t, err) {
+		assert.Contains(t, w.Body.String(), `"truncated":true`)
+	}
+}
+
+func TestWriteProblem_xml(t *testing.T) {
+	r := httptest.NewRequest("POST", "/", nil)
+	r.Header.Set("Accept", "application/json;q=0.5, application/problem+xml")
+	w := httptest.NewRecorder()
+
+	err := WriteProblem(w, r, Errors{StructError{Field: "email", Message: ErrEmail}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 422, w.Code)
+		assert.Equal(t, "application/problem+xml", w.Header().Get("Content-Type"))
+		assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+`<problem xmlns="urn:ietf:rfc:7807"><title>Unprocessable Entity</title><status>422</status><detail>validation failed</detail><errors><i><pointer>/email</pointer><field>email</field><code>email.invalid</code><message>invalid email</message></i></errors></problem>`, w.Body.String())
+
+		var p Problem
+		if assert.NoError(t, xml.Unmarshal(w.Body.Bytes(), &p)) {
+			p.XMLName = xml.Name{}
+			assert.Equal(t, NewProblem(Errors{StructError{Field: "email", Message: ErrEmail}}), p)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	err = WriteProblem(w, r, Errors{StructError{Field: "email", Message: ErrEmail}, ErrTruncated})
+	if assert.NoError(t, err) {
+		assert.Contains(t, w.Body.String(), `</errors><truncated>true</truncated></problem>`)
+	}
+
+	w = httptest.NewRecorder()
+	err = WriteProblem(w, r, ErrEncoding)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 415, w.Code)
+		assert.False(t, strings.Contains(w.Body.String(), "<errors>"))
+	}
+}
+
+func TestAcceptsXML(t *testing.T) {
+	tests := []struct {
+		Header string
+		XML    bool
+	}{
+		{"", false},
+		{"*/*", false},
+		{"application/json", false},
+		{"application/xml", true},
+		{"text/xml, application/json", true},
+		{"application/json, text/xml", false},
+		{"application/problem+json;q=0.1, application/problem+xml;q=0.9", true},
+		{"application/xml;q=0", false},
+		{"text/html", false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.XML, acceptsXML(test.Header), test.Header)
+	}
+}
+
+func TestForm_ParseRequestAndValidate_problem(t *testing.T) {
+	r := httptest.NewRequest("POST", "/", strings.NewReader(`{"email": 1}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	var body struct {
+		Email Email `json:"email"`
+	}
+	err := form.ParseRequestAndValidate(r, &body)
+	p := NewProblem(err)
+	assert.Equal(t, http.StatusBadRequest, p.Status)
+	assert.Equal(t, ProblemErrors{{Pointer: "/email", Field: "email", Code: "decode.type", Message: "expected string"}}, p.Errors)
+
+	r = httptest.NewRequest("POST", "/", strings.NewReader(`{"email": 1}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept-Language", "de")
+	err = form.ParseRequestAndValidate(r, &body)
+	assert.Equal(t, "email: string erwartet", err.Error())
+}
@@ -0,0 +1,59 @@
+package legit
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// sniffLen is the number of bytes of a body considered when sniffing its
+// MIME type
+const sniffLen = 512
+
+// sniffContentType returns the MIME type of a body detected from its first
+// bytes, which are replayed by the returned reader, or an empty string if
+// it is not recognised
+func sniffContentType(r io.Reader) (io.Reader, string) {
+	br := bufio.NewReaderSize(r, sniffLen)
+	b, _ := br.Peek(sniffLen)
+
+	return br, detectContentType(b)
+}
+
+// returns the MIME type of JSON, XML and URL encoded form data, detected by the
+// first non-whitespace character, or an empty string if not recognised
+func detectContentType(b []byte) string {
+	b = bytes.TrimPrefix(b, []byte{0xEF, 0xBB, 0xBF})
+	b = bytes.TrimLeft(b, " \t\r\n")
+	if len(b) < 1 {
+		return ""
+	}
+
+	switch b[0] {
+	case '{', '[':
+		return "application/json"
+	case '<':
+		return "application/xml"
+	}
+
+	if isFormData(b) {
+		return "application/x-www-form-urlencoded"
+	}
+
+	return ""
+}
+
+// returns true if b begins with a key of URL encoded form data, i.e.
+// "name=foo&..."
+func isFormData(b []byte) bool {
+	for i, c := range b {
+		switch {
+		case c == '=':
+			return i > 0
+		case c == '&' || c == ' ' || c < 0x20 || c > 0x7E:
+			return false
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,108 @@
+package legit
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectContentType(t *testing.T) {
+	tests := []struct {
+		Body string
+		MIME string
+	}{
+		{`{"name":"foo"}`, "application/json"},
+		{" \r\n\t[1, 2]", "application/json"},
+		{"\xEF\xBB\xBF{}", "application/json"},
+		{`<?xml version="1.0"?><user/>`, "application/xml"},
+		{"\n<user/>", "application/xml"},
+		{"name=foo&age=1", "application/x-www-form-urlencoded"},
+		{"address%5Bcity%5D=London", "application/x-www-form-urlencoded"},
+		{"=foo", ""},
+		{"hello world", ""},
+		{"name foo=bar", ""},
+		{"a&b=c", ""},
+		{"--boundary\r\n", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.MIME, detectContentType([]byte(test.Body)), test.Body)
+	}
+}
+
+func TestSniffContentType(t *testing.T) {
+	body := `{"name":"` + strings.Repeat("a", 1024) + `"}`
+
+	r, mime := sniffContentType(strings.NewReader(body))
+	assert.Equal(t, "application/json", mime)
+
+	b, err := ioutil.ReadAll(r)
+	if assert.NoError(t, err) {
+		assert.Equal(t, body, string(b))
+	}
+}
+
+func TestForm_ParseRequestAndValidate_missingContentType(t *testing.T) {
+	newRequest := func(body string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	}
+
+	var u charsetUser
+	assert.Equal(t, ErrEncoding, form.ParseRequestAndValidate(newRequest(`{"name":"foo"}`), &u))
+
+	f := NewForm()
+	f.DefaultContentType = "application/json"
+	if assert.NoError(t, f.ParseRequestAndValidate(newRequest(`{"name":"foo"}`), &u)) {
+		assert.Equal(t, "foo", u.Name)
+	}
+	assert.IsType(t, &DecodeError{}, f.ParseRequestAndValidate(newRequest(`<user><name>bar</name></user>`), &u))
+
+	f.Decoders = Decoders{JSON{}, XML{}, URLEncoded{}}
+	f.SniffContentType = true
+
+	tests := []struct {
+		Body string
+		Name string
+	}{
+		{`{"name":"json"}`, "json"},
+		{`<user><name>xml</name></user>`, "xml"},
+		{`name=form`, "form"},
+	}
+
+	for _, test := range tests {
+		u = charsetUser{}
+		if assert.NoError(t, f.ParseRequestAndValidate(newRequest(test.Body), &u), test.Body) {
+			assert.Equal(t, test.Name, u.Name)
+		}
+	}
+
+	// bodies which are not recognised fall back to the default
+	assert.IsType(t, &DecodeError{}, f.ParseRequestAndValidate(newRequest(`hello world`), &u))
+
+	f.DefaultContentType = ""
+	assert.Equal(t, ErrEncoding, f.ParseRequestAndValidate(newRequest(`hello world`), &u))
+
+	// sniffed types without a decoder fall back to the default
+	f.Decoders = Decoders{JSON{}}
+	assert.Equal(t, ErrEncoding, f.ParseRequestAndValidate(newRequest(`<user/>`), &u))
+
+	// a declared type is never sniffed
+	r := newRequest(`{"name":"foo"}`)
+	r.Header.Set("Content-Type", "application/xml")
+	assert.Equal(t, ErrEncoding, f.ParseRequestAndValidate(r, &u))
+}
+
+func TestForm_ParseAndValidate_sniff(t *testing.T) {
+	f := NewForm()
+	f.SniffContentType = true
+
+	var u charsetUser
+	if assert.NoError(t, f.ParseAndValidate(strings.NewReader(`{"name":"foo"}`), "", &u)) {
+		assert.Equal(t, "foo", u.Name)
+	}
+}
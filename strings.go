@@ -7,7 +7,9 @@ import (
 	"unicode"
 )
 
-var errUnsupportedScan = errors.New("unsupported scan")
+// ErrUnsupportedScan is returned when a string validator is scanned from a
+// non-string value.
+var ErrUnsupportedScan = errors.New("unsupported scan")
 
 // Lower validates any string not containing any uppercase characters.
 type Lower string
@@ -18,19 +20,20 @@ func (l *Lower) Scan(src interface{}) error {
 		return nil
 	}
 
-	return errUnsupportedScan
+	return ErrUnsupportedScan
 }
 
 func (l Lower) Value() (driver.Value, error) {
 	return string(l), nil
 }
 
-var errLower = errors.New("string is not lowercase")
+// ErrLower is returned when a Lower contains non-lowercase characters.
+var ErrLower = NewValidationError("string.not_lowercase", "string is not lowercase", nil)
 
 func (l Lower) Validate() error {
 	for _, r := range l {
 		if !unicode.IsLower(r) {
-			return errLower
+			return ErrLower
 		}
 	}
 
@@ -46,19 +49,20 @@ func (u *Upper) Scan(src interface{}) error {
 		return nil
 	}
 
-	return errUnsupportedScan
+	return ErrUnsupportedScan
 }
 
 func (u Upper) Value() (driver.Value, error) {
 	return string(u), nil
 }
 
-var errUpper = errors.New("string is not uppercase")
+// ErrUpper is returned when an Upper contains non-uppercase characters.
+var ErrUpper = NewValidationError("string.not_uppercase", "string is not uppercase", nil)
 
 func (u Upper) Validate() error {
 	for _, r := range u {
 		if !unicode.IsUpper(r) {
-			return errUpper
+			return ErrUpper
 		}
 	}
 
@@ -74,19 +78,20 @@ func (ns *NoSpace) Scan(src interface{}) error {
 		return nil
 	}
 
-	return errUnsupportedScan
+	return ErrUnsupportedScan
 }
 
 func (ns NoSpace) Value() (driver.Value, error) {
 	return string(ns), nil
 }
 
-var errNoSpace = errors.New("string contains whitespace")
+// ErrNoSpace is returned when a NoSpace contains whitespace.
+var ErrNoSpace = NewValidationError("string.contains_whitespace", "string contains whitespace", nil)
 
 func (ns NoSpace) Validate() error {
 	for _, r := range ns {
 		if unicode.IsSpace(r) {
-			return errNoSpace
+			return ErrNoSpace
 		}
 	}
 
@@ -102,19 +107,20 @@ func (p *Printable) Scan(src interface{}) error {
 		return nil
 	}
 
-	return errUnsupportedScan
+	return ErrUnsupportedScan
 }
 
 func (p Printable) Value() (driver.Value, error) {
 	return string(p), nil
 }
 
-var errPrintable = errors.New("string contains non-printing characters")
+// ErrPrintable is returned when a Printable contains non-printing characters.
+var ErrPrintable = NewValidationError("string.not_printable", "string contains non-printing characters", nil)
 
 func (p Printable) Validate() error {
 	for _, r := range p {
 		if !unicode.IsPrint(r) {
-			return errPrintable
+			return ErrPrintable
 		}
 	}
 
@@ -130,19 +136,20 @@ func (a *Alpha) Scan(src interface{}) error {
 		return nil
 	}
 
-	return errUnsupportedScan
+	return ErrUnsupportedScan
 }
 
 func (a Alpha) Value() (driver.Value, error) {
 	return string(a), nil
 }
 
-var errAlpha = errors.New("string contains non-alpha characters")
+// ErrAlpha is returned when an Alpha contains non-letter characters.
+var ErrAlpha = NewValidationError("string.not_alpha", "string contains non-alpha characters", nil)
 
 func (a Alpha) Validate() error {
 	for _, r := range a {
 		if !unicode.IsLetter(r) {
-			return errAlpha
+			return ErrAlpha
 		}
 	}
 
@@ -158,19 +165,20 @@ func (n *Number) Scan(src interface{}) error {
 		return nil
 	}
 
-	return errUnsupportedScan
+	return ErrUnsupportedScan
 }
 
 func (n Number) Value() (driver.Value, error) {
 	return string(n), nil
 }
 
-var errNumber = errors.New("string contains non-numeric characters")
+// ErrNumber is returned when a Number contains non-numeric characters.
+var ErrNumber = NewValidationError("string.not_numeric", "string contains non-numeric characters", nil)
 
 func (n Number) Validate() error {
 	for _, r := range n {
 		if !unicode.IsNumber(r) {
-			return errNumber
+			return ErrNumber
 		}
 	}
 
@@ -187,18 +195,19 @@ func (f *Float) Scan(src interface{}) error {
 		return nil
 	}
 
-	return errUnsupportedScan
+	return ErrUnsupportedScan
 }
 
 func (f Float) Value() (driver.Value, error) {
 	return string(f), nil
 }
 
-var errFloat = errors.New("float contains non-numeric characters")
+// ErrFloat is returned when a Float is not a valid decimal number.
+var ErrFloat = NewValidationError("string.not_float", "float contains non-numeric characters", nil)
 
 func (f Float) Validate() error {
 	if len(f) == 0 {
-		return errFloat
+		return ErrFloat
 	}
 
 	s := string(f)
@@ -207,16 +216,16 @@ func (f Float) Validate() error {
 	}
 
 	if strings.Count(s, ".") > 1 {
-		return errFloat
+		return ErrFloat
 	} else if s[0] == '.' || s[len(s)-1] == '.' {
-		return errFloat
+		return ErrFloat
 	}
 
 	i := strings.IndexFunc(s, func(r rune) bool {
 		return !((r >= '0' && r <= '9') || r == '.')
 	})
 	if i > -1 {
-		return errFloat
+		return ErrFloat
 	}
 
 	return nil
@@ -231,19 +240,21 @@ func (a *Alphanumeric) Scan(src interface{}) error {
 		return nil
 	}
 
-	return errUnsupportedScan
+	return ErrUnsupportedScan
 }
 
 func (a Alphanumeric) Value() (driver.Value, error) {
 	return string(a), nil
 }
 
-var errAlphanumeric = errors.New("string contains non-alphanumeric characters")
+// ErrAlphanumeric is returned when an Alphanumeric contains characters other
+// than letters and numbers.
+var ErrAlphanumeric = NewValidationError("string.not_alphanumeric", "string contains non-alphanumeric characters", nil)
 
 func (a Alphanumeric) Validate() error {
 	for _, r := range a {
 		if !unicode.IsLetter(r) && !unicode.IsNumber(r) {
-			return errAlphanumeric
+			return ErrAlphanumeric
 		}
 	}
 
@@ -259,19 +270,20 @@ func (a *ASCII) Scan(src interface{}) error {
 		return nil
 	}
 
-	return errUnsupportedScan
+	return ErrUnsupportedScan
 }
 
 func (a ASCII) Value() (driver.Value, error) {
 	return string(a), nil
 }
 
-var errASCII = errors.New("string contains non-ASCII characters")
+// ErrASCII is returned when an ASCII contains non-ASCII characters.
+var ErrASCII = NewValidationError("string.not_ascii", "string contains non-ASCII characters", nil)
 
 func (a ASCII) Validate() error {
 	for _, r := range a {
 		if r > unicode.MaxASCII {
-			return errASCII
+			return ErrASCII
 		}
 	}
 
@@ -287,18 +299,19 @@ func (r *Required) Scan(src interface{}) error {
 		return nil
 	}
 
-	return errUnsupportedScan
+	return ErrUnsupportedScan
 }
 
 func (r Required) Value() (driver.Value, error) {
 	return string(r), nil
 }
 
-var errRequired = errors.New("string is required")
+// ErrRequired is returned when a Required is empty.
+var ErrRequired = NewValidationError("string.required", "string is required", nil)
 
 func (r Required) Validate() error {
 	if len(r) < 1 {
-		return errRequired
+		return ErrRequired
 	}
 
 	return nil
@@ -9,31 +9,31 @@ import (
 )
 
 func TestLower(t *testing.T) {
-	testString(t, Lower("foo"), Lower("FOO"), errLower)
+	testString(t, Lower("foo"), Lower("FOO"), ErrLower)
 }
 
 func TestUpper(t *testing.T) {
-	testString(t, Upper("FOO"), Upper("foo"), errUpper)
+	testString(t, Upper("FOO"), Upper("foo"), ErrUpper)
 }
 
 func TestNoSpace(t *testing.T) {
-	testString(t, NoSpace("foo"), NoSpace(" foo\t bar "), errNoSpace)
+	testString(t, NoSpace("foo"), NoSpace(" foo\t bar "), ErrNoSpace)
 }
 
 func TestPrintable(t *testing.T) {
-	testString(t, Printable("foo"), Printable("\x00foo"), errPrintable)
+	testString(t, Printable("foo"), Printable("\x00foo"), ErrPrintable)
 }
 
 func TestAlpha(t *testing.T) {
-	testString(t, Alpha("foo"), Alpha("abc123"), errAlpha)
+	testString(t, Alpha("foo"), Alpha("abc123"), ErrAlpha)
 }
 
 func TestNumber(t *testing.T) {
-	testString(t, Number("1234"), Number("foo"), errNumber)
+	testString(t, Number("1234"), Number("foo"), ErrNumber)
 }
 
 func TestFloat(t *testing.T) {
-	testString(t, Float("-1.23"), Float("foo"), errFloat)
+	testString(t, Float("-1.23"), Float("foo"), ErrFloat)
 
 	assert.Error(t, Float("").Validate())
 	assert.Error(t, Float("1.2.3").Validate())
@@ -42,15 +42,15 @@ func TestFloat(t *testing.T) {
 }
 
 func TestAlphanumeric(t *testing.T) {
-	testString(t, Alphanumeric("abc123"), Alphanumeric(" foo! "), errAlphanumeric)
+	testString(t, Alphanumeric("abc123"), Alphanumeric(" foo! "), ErrAlphanumeric)
 }
 
 func TestASCII(t *testing.T) {
-	testString(t, ASCII("abc123"), ASCII("föö"), errASCII)
+	testString(t, ASCII("abc123"), ASCII("föö"), ErrASCII)
 }
 
 func TestRequired(t *testing.T) {
-	testString(t, Required("foo"), Required(""), errRequired)
+	testString(t, Required("foo"), Required(""), ErrRequired)
 }
 
 func testString(t *testing.T, pass, fail Validator, failErr error) {
@@ -70,25 +70,25 @@ func TestStringScan(t *testing.T) {
 		Value   sql.Scanner
 		Error   error
 	}{
-		{"Lower/Fail", (*Lower)(strPtr("")), 0, nil, errUnsupportedScan},
+		{"Lower/Fail", (*Lower)(strPtr("")), 0, nil, ErrUnsupportedScan},
 		{"Lower/Success", (*Lower)(strPtr("")), "foo", (*Lower)(strPtr("foo")), nil},
-		{"Upper/Fail", (*Upper)(strPtr("")), 0, nil, errUnsupportedScan},
+		{"Upper/Fail", (*Upper)(strPtr("")), 0, nil, ErrUnsupportedScan},
 		{"Upper/Success", (*Upper)(strPtr("")), "foo", (*Upper)(strPtr("foo")), nil},
-		{"NoSpace/Fail", (*NoSpace)(strPtr("")), 0, nil, errUnsupportedScan},
+		{"NoSpace/Fail", (*NoSpace)(strPtr("")), 0, nil, ErrUnsupportedScan},
 		{"NoSpace/Success", (*NoSpace)(strPtr("")), "foo", (*NoSpace)(strPtr("foo")), nil},
-		{"Printable/Fail", (*Printable)(strPtr("")), 0, nil, errUnsupportedScan},
+		{"Printable/Fail", (*Printable)(strPtr("")), 0, nil, ErrUnsupportedScan},
 		{"Printable/Success", (*Printable)(strPtr("")), "foo", (*Printable)(strPtr("foo")), nil},
-		{"Alpha/Fail", (*Alpha)(strPtr("")), 0, nil, errUnsupportedScan},
+		{"Alpha/Fail", (*Alpha)(strPtr("")), 0, nil, ErrUnsupportedScan},
 		{"Alpha/Success", (*Alpha)(strPtr("")), "foo", (*Alpha)(strPtr("foo")), nil},
-		{"Number/Fail", (*Number)(strPtr("")), 0, nil, errUnsupportedScan},
+		{"Number/Fail", (*Number)(strPtr("")), 0, nil, ErrUnsupportedScan},
 		{"Number/Success", (*Number)(strPtr("")), "foo", (*Number)(strPtr("foo")), nil},
-		{"Float/Fail", (*Float)(strPtr("")), 0, nil, errUnsupportedScan},
+		{"Float/Fail", (*Float)(strPtr("")), 0, nil, ErrUnsupportedScan},
 		{"Float/Success", (*Float)(strPtr("")), "foo", (*Float)(strPtr("foo")), nil},
-		{"Alphanumeric/Fail", (*Alphanumeric)(strPtr("")), 0, nil, errUnsupportedScan},
+		{"Alphanumeric/Fail", (*Alphanumeric)(strPtr("")), 0, nil, ErrUnsupportedScan},
 		{"Alphanumeric/Success", (*Alphanumeric)(strPtr("")), "foo", (*Alphanumeric)(strPtr("foo")), nil},
-		{"ASCII/Fail", (*ASCII)(strPtr("")), 0, nil, errUnsupportedScan},
+		{"ASCII/Fail", (*ASCII)(strPtr("")), 0, nil, ErrUnsupportedScan},
 		{"ASCII/Success", (*ASCII)(strPtr("")), "foo", (*ASCII)(strPtr("foo")), nil},
-		{"Required/Fail", (*Required)(strPtr("")), 0, nil, errUnsupportedScan},
+		{"Required/Fail", (*Required)(strPtr("")), 0, nil, ErrUnsupportedScan},
 		{"Required/Success", (*Required)(strPtr("")), "foo", (*Required)(strPtr("foo")), nil},
 	}
 
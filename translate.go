@@ -0,0 +1,212 @@
+package legit
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Catalog maps the codes of validation errors to message templates in a
+// single language. Templates may contain the same {name} placeholders as
+// ValidationError messages.
+type Catalog map[string]string
+
+// Translator rewrites the messages of validation errors into other languages
+// using catalogs registered for each language tag
+type Translator struct {
+	mu       sync.RWMutex
+	catalogs map[string]Catalog
+}
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var translator = NewTranslator()
+
+// NewTranslator returns a Translator with the catalogs included with legit
+// for the built-in validators
+func NewTranslator() *Translator {
+	t := &Translator{}
+	if err := t.Load(locales, "locales"); err != nil {
+		panic("legit: invalid built-in catalog: " + err.Error())
+	}
+
+	return t
+}
+
+// RegisterCatalog adds the messages of a catalog to the default Translator
+// for a language, replacing any existing messages with the same code
+func RegisterCatalog(lang string, c Catalog) {
+	translator.Register(lang, c)
+}
+
+// Register adds the messages of a catalog for a language, replacing any
+// existing messages with the same code
+func (t *Translator) Register(lang string, c Catalog) {
+	lang = strings.ToLower(lang)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.catalogs == nil {
+		t.catalogs = make(map[string]Catalog)
+	}
+
+	// catalogs are copied on write, as those returned by catalog are read
+	// without holding the lock
+	old := t.catalogs[lang]
+	dst := make(Catalog, len(old)+len(c))
+	for code, msg := range old {
+		dst[code] = msg
+	}
+	for code, msg := range c {
+		dst[code] = msg
+	}
+
+	t.catalogs[lang] = dst
+}
+
+// Load registers a catalog from every JSON file in a directory of fsys, such
+// as an embed.FS. The language of each catalog is taken from its file name,
+// i.e. "de.json" or "pt-BR.json".
+func (t *Translator) Load(fsys fs.FS, dir string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		b, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+
+		var c Catalog
+		if err := json.Unmarshal(b, &c); err != nil {
+			return err
+		}
+
+		t.Register(strings.TrimSuffix(path.Base(file), ".json"), c)
+	}
+
+	return nil
+}
+
+// returns the catalog for a language, falling back to the base language of a
+// regional tag such as "pt-BR". The catalog must not be modified.
+func (t *Translator) catalog(lang string) Catalog {
+	lang = strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if c, ok := t.catalogs[lang]; ok {
+		return c
+	}
+
+	if i := strings.Index(lang, "-"); i > -1 {
+		return t.catalogs[lang[:i]]
+	}
+
+	return nil
+}
+
+// Negotiate returns the most preferred language of an Accept-Language header
+// which has a catalog, or an empty string if there is none
+func (t *Translator) Negotiate(header string) string {
+	type pref struct {
+		lang string
+		q    float64
+	}
+
+	var prefs []pref
+	for _, part := range strings.Split(header, ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang = strings.TrimSpace(lang)
+		if lang == "" || lang == "*" {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+
+		if q > 0 {
+			prefs = append(prefs, pref{lang, q})
+		}
+	}
+
+	sort.SliceStable(prefs, func(i, j int) bool {
+		return prefs[i].q > prefs[j].q
+	})
+
+	for _, p := range prefs {
+		if t.catalog(p.lang) != nil {
+			return p.lang
+		}
+	}
+
+	return ""
+}
+
+// Translate rewrites every ValidationError within err into a language using
+// the default Translator. Errors without a code, or whose code is not in the
+// language's catalog, are left untouched.
+func Translate(err error, lang string) error {
+	return translator.Translate(err, lang)
+}
+
+// Translate rewrites every ValidationError within err into a language.
+// Errors without a code, or whose code is not in the language's catalog, are
+// left untouched.
+func (t *Translator) Translate(err error, lang string) error {
+	c := t.catalog(lang)
+	if c == nil {
+		return err
+	}
+
+	return c.translate(err)
+}
+
+func (c Catalog) translate(err error) error {
+	switch e := err.(type) {
+	case Errors:
+		errs := make(Errors, len(e))
+		for i, err := range e {
+			errs[i] = c.translate(err)
+		}
+		return errs
+	case SummaryError:
+		return SummaryError{c.translate(e.Errors).(Errors)}
+	case StructError:
+		e.Message = c.translate(e.Message)
+		return e
+	case SliceError:
+		e.Message = c.translate(e.Message)
+		return e
+	case MapError:
+		e.Message = c.translate(e.Message)
+		return e
+	case FieldError:
+		e.Message = c.translate(e.Message)
+		return e
+	case *DecodeError:
+		if len(e.Errors) > 0 {
+			return &DecodeError{Err: e.Err, Errors: c.translate(e.Errors).(Errors)}
+		}
+	case *ValidationError:
+		if msg, ok := c[e.Code]; ok {
+			return NewValidationError(e.Code, msg, e.Params)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,186 @@
+package legit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// every error returned by a built-in validator, which must be present in
+// every catalog
+var builtinErrors = []*ValidationError{
+	ErrLower, ErrUpper, ErrNoSpace, ErrPrintable, ErrAlpha, ErrNumber, ErrFloat,
+	ErrAlphanumeric, ErrASCII, ErrRequired, ErrEmail, ErrCreditCard, ErrUUID,
+	ErrUUID3, ErrUUID4, ErrUUID5, ErrPositive, ErrNegative, ErrFileTooLarge,
+	ErrFileType, ErrFileExtension, ErrDecodeType, ErrDecodeSyntax, ErrDecodeEmpty,
+	ErrDecodeTruncated, ErrDecodeUnknownField, ErrDecodeDuplicateKey,
+	ErrDecodeTrailingData, ErrBodyTooLarge, ErrTooDeep, ErrStringTooLong,
+	ErrArrayTooLong, ErrTooManyTokens, ErrTooManyAttributes,
+}
+
+func TestCatalogs(t *testing.T) {
+	files, err := fs.Glob(locales, "locales/*.json")
+	if !assert.NoError(t, err) || !assert.Len(t, files, 6) {
+		return
+	}
+
+	for _, file := range files {
+		b, err := fs.ReadFile(locales, file)
+		if !assert.NoError(t, err) {
+			continue
+		}
+
+		var c Catalog
+		if assert.NoError(t, json.Unmarshal(b, &c), file) {
+			for _, ve := range builtinErrors {
+				assert.NotEmpty(t, c[ve.Code], "%s missing %s", file, ve.Code)
+			}
+		}
+	}
+
+	// the English catalog must match the messages of the validators
+	en := translator.catalog("en")
+	for _, ve := range builtinErrors {
+		assert.Equal(t, ve.Message, en[ve.Code])
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	err := Translate(Errors{
+		StructError{Field: "email", Message: ErrEmail},
+		StructError{Field: "tags", Message: Errors{SliceError{Index: 0, Message: ErrLower}}},
+		StructError{Field: "names", Message: Errors{MapError{Key: "a", Message: ErrRequired}}},
+		FieldError{Path: Path{"age"}, Message: ErrPositive},
+		StructError{Field: "other", Message: ErrStrict},
+	}, "de-DE")
+
+	assert.Equal(t, Errors{
+		StructError{Field: "email", Message: NewValidationError("email.invalid", "ungültige E-Mail-Adresse", nil)},
+		StructError{Field: "tags", Message: Errors{SliceError{Index: 0, Message: NewValidationError("string.not_lowercase", "Zeichenkette ist nicht in Kleinbuchstaben", nil)}}},
+		StructError{Field: "names", Message: Errors{MapError{Key: "a", Message: NewValidationError("string.required", "Wert ist erforderlich", nil)}}},
+		FieldError{Path: Path{"age"}, Message: NewValidationError("number.not_positive", "Zahl ist nicht positiv", nil)},
+		StructError{Field: "other", Message: ErrStrict},
+	}, err)
+
+	err = Translate(SummaryError{Errors{StructError{Field: "email", Message: ErrEmail}}}, "de")
+	assert.Equal(t, SummaryError{Errors{StructError{Field: "email", Message: NewValidationError("email.invalid", "ungültige E-Mail-Adresse", nil)}}}, err)
+
+	assert.Equal(t, ErrEmail, Translate(ErrEmail, "xx"))
+	assert.Nil(t, Translate(nil, "de"))
+}
+
+func TestTranslator_Register(t *testing.T) {
+	tr := NewTranslator()
+	tr.Register("de", Catalog{"number.min": "muss mindestens {min} sein"})
+	tr.Register("pt-BR", Catalog{"email.invalid": "e-mail inválido (BR)"})
+
+	err := tr.Translate(NewValidationError("number.min", "must be at least {min}", map[string]interface{}{"min": 18}), "de")
+	assert.EqualError(t, err, "muss mindestens 18 sein")
+
+	assert.EqualError(t, tr.Translate(ErrEmail, "pt_br"), "e-mail inválido (BR)")
+	assert.EqualError(t, tr.Translate(ErrEmail, "pt-PT"), "e-mail inválido")
+
+	// built-in messages are kept when registering additional messages
+	assert.EqualError(t, tr.Translate(ErrEmail, "de"), "ungültige E-Mail-Adresse")
+}
+
+func TestTranslator_Register_concurrent(t *testing.T) {
+	tr := NewTranslator()
+
+	// start registering and translating together so they interleave
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		<-start
+		for i := 0; i < 10000; i++ {
+			tr.Register("de", Catalog{"number.min": "muss mindestens {min} sein " + strconv.Itoa(i)})
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		<-start
+		for i := 0; i < 10000; i++ {
+			tr.Translate(Errors{StructError{Field: "email", Message: ErrEmail}}, "de")
+		}
+	}()
+
+	close(start)
+	wg.Wait()
+
+	assert.EqualError(t, tr.Translate(ErrEmail, "de"), "ungültige E-Mail-Adresse")
+}
+
+func TestTranslator_Load(t *testing.T) {
+	fsys := fstest.MapFS{
+		"i18n/nl.json":  {Data: []byte(`{"email.invalid": "ongeldig e-mailadres"}`)},
+		"i18n/bad.txt":  {Data: []byte(`ignored`)},
+		"other/xx.json": {Data: []byte(`{"email.invalid": "ignored"}`)},
+	}
+
+	tr := &Translator{}
+	if assert.NoError(t, tr.Load(fsys, "i18n")) {
+		assert.EqualError(t, tr.Translate(ErrEmail, "nl"), "ongeldig e-mailadres")
+		assert.Nil(t, tr.catalog("xx"))
+	}
+
+	fsys["i18n/broken.json"] = &fstest.MapFile{Data: []byte(`{`)}
+	assert.Error(t, tr.Load(fsys, "i18n"))
+}
+
+func TestTranslator_Negotiate(t *testing.T) {
+	tests := []struct {
+		Header string
+		Lang   string
+	}{
+		{"", ""},
+		{"*", ""},
+		{"de", "de"},
+		{"de-CH, fr;q=0.9", "de-CH"},
+		{"xx, fr;q=0.5, es;q=0.8", "es"},
+		{"fr;q=0, it", "it"},
+		{"xx-YY, zz", ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.Lang, translator.Negotiate(test.Header), test.Header)
+	}
+}
+
+func TestForm_ParseRequestAndValidate_translate(t *testing.T) {
+	r := &http.Request{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{"email": "foo"}`))),
+		Header: http.Header{
+			"Content-Type":    []string{"application/json"},
+			"Accept-Language": []string{"fr-CA, en;q=0.8"},
+		},
+	}
+
+	var body struct {
+		Email Email `json:"email"`
+	}
+	err := form.ParseRequestAndValidate(r, &body)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{StructError{Field: "email", Message: NewValidationError("email.invalid", "adresse e-mail invalide", nil)}}, err)
+	}
+
+	f := form
+	f.Translator = nil
+	r.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"email": "foo"}`)))
+	err = f.ParseRequestAndValidate(r, &body)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{StructError{Field: "email", Message: ErrEmail}}, err)
+	}
+}
@@ -0,0 +1,378 @@
+package legit
+
+import (
+	"encoding"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// URLEncoded decoder can decode any HTML form body with the MIME type
+// "application/x-www-form-urlencoded". Values are bound to struct fields named
+// by their "form" tag, or their Go field name. Nested fields are addressed by
+// bracket or dot notation, i.e. "address[city]" or "address.city", and slices
+// by repeated keys or indexes, i.e. "tags=a&tags=b" or "items[0][name]=a".
+// Indexes only order the elements of a slice, they are not positions, so
+// "items[5]" alone decodes to a slice of one element. Values are transcoded to
+// UTF-8 from the charset parameter of the MIME type.
+type URLEncoded struct{}
+
+func (u URLEncoded) Match(mime string) bool {
+	return isMediaType(mime, "application/x-www-form-urlencoded")
+}
+
+func (u URLEncoded) Decode(r io.Reader, dst interface{}) error {
+	return u.DecodeParams(r, nil, dst)
+}
+
+func (u URLEncoded) DecodeParams(r io.Reader, params map[string]string, dst interface{}) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	values, err := url.ParseQuery(string(b))
+	if err != nil {
+		return err
+	}
+
+	// percent encoded bytes are in the declared charset, so values are
+	// transcoded once unescaped
+	if charset := params["charset"]; charset != "" {
+		values, err = transcodeValues(values, charset)
+		if err != nil {
+			return err
+		}
+	}
+
+	return bindValues(values, dst)
+}
+
+// returns the keys and values of url.Values transcoded to UTF-8 from charset
+func transcodeValues(values url.Values, charset string) (url.Values, error) {
+	if _, err := charsetReader(charset, nil); err != nil {
+		return nil, err
+	}
+
+	tv := make(url.Values, len(values))
+	for key, vs := range values {
+		tk, err := transcodeString(key, charset)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range vs {
+			t, err := transcodeString(v, charset)
+			if err != nil {
+				return nil, err
+			}
+			tv[tk] = append(tv[tk], t)
+		}
+	}
+
+	return tv, nil
+}
+
+func (u URLEncoded) TagName() string {
+	return "form"
+}
+
+// BindError is returned when a form value cannot be assigned to a field. Type
+// names the expected type as in JSON, such as "number", rather than as the Go
+// type of the field.
+type BindError struct {
+	Key   string
+	Value string
+	Type  string
+}
+
+// returns the string representation of the value and field which could not be
+// bound
+func (be *BindError) Error() string {
+	return fmt.Sprintf("cannot bind %q to %s field %s", be.Value, be.Type, be.Key)
+}
+
+// formNode is a key of url.Values, or of uploaded files, split into its
+// nested parts
+type formNode struct {
+	key      string
+	values   []string
+	files    []*multipart.FileHeader
+	children map[string]*formNode
+}
+
+func (n *formNode) child(name string) *formNode {
+	if n.children == nil {
+		n.children = make(map[string]*formNode)
+	}
+
+	c, ok := n.children[name]
+	if !ok {
+		c = &formNode{key: name}
+		if n.key != "" {
+			c.key = n.key + "." + name
+		}
+		n.children[name] = c
+	}
+
+	return c
+}
+
+// bind url.Values to the value pointed to by dst
+func bindValues(values url.Values, dst interface{}) error {
+	return bindForm(values, nil, dst)
+}
+
+// bind url.Values and uploaded files to the value pointed to by dst
+func bindForm(values url.Values, files map[string][]*multipart.FileHeader, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("legit: cannot bind to non-pointer %T", dst)
+	}
+
+	root := &formNode{}
+	for key, vs := range values {
+		n := root
+		for _, part := range splitKey(key) {
+			n = n.child(part)
+		}
+		n.values = append(n.values, vs...)
+	}
+	for key, fs := range files {
+		n := root
+		for _, part := range splitKey(key) {
+			n = n.child(part)
+		}
+		n.files = append(n.files, fs...)
+	}
+
+	return bindNode(v.Elem(), root)
+}
+
+// split a key into its nested parts, i.e. "items[0][name]", "items.0.name"
+// and "items[0].name" are all split into "items", "0" and "name"
+func splitKey(key string) []string {
+	parts := strings.FieldsFunc(key, func(r rune) bool {
+		return r == '[' || r == ']' || r == '.'
+	})
+
+	if len(parts) < 1 {
+		return []string{key}
+	}
+
+	return parts
+}
+
+var textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+func bindNode(v reflect.Value, n *formNode) error {
+	if fv, ok := fileOf(v); ok {
+		return bindFile(fv, n)
+	}
+
+	if v.CanAddr() && v.Kind() != reflect.Ptr && reflect.PtrTo(v.Type()).Implements(textUnmarshaler) {
+		if len(n.values) < 1 {
+			return nil
+		}
+
+		err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(n.values[0]))
+		if err != nil {
+			return &BindError{Key: n.key, Value: n.values[0], Type: typeName(v.Type())}
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return bindNode(v.Elem(), n)
+	case reflect.Struct:
+		return bindStruct(v, n)
+	case reflect.Slice:
+		return bindSlice(v, n)
+	case reflect.Map:
+		return bindMap(v, n)
+	}
+
+	if len(n.values) < 1 {
+		return nil
+	}
+
+	return bindScalar(v, n.key, n.values[0])
+}
+
+func bindStruct(v reflect.Value, n *formNode) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		ft := t.Field(i)
+
+		name, tagged, skip := fieldName(ft, "form")
+		if skip {
+			continue
+		}
+
+		// embedded structs have their fields promoted
+		if ft.Anonymous && !tagged && isEmbeddedStruct(ft.Type) {
+			fv := v.Field(i)
+			if ft.Type.Kind() == reflect.Ptr {
+				if !fv.CanSet() {
+					continue
+				}
+				if fv.IsNil() {
+					fv.Set(reflect.New(ft.Type.Elem()))
+				}
+				fv = fv.Elem()
+			}
+
+			if err := bindStruct(fv, n); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if len(ft.PkgPath) > 0 {
+			continue
+		}
+
+		if c, ok := n.children[name]; ok {
+			if err := bindNode(v.Field(i), c); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func bindSlice(v reflect.Value, n *formNode) error {
+	// repeated files
+	if len(n.files) > 0 && isFileType(v.Type().Elem()) {
+		s := reflect.MakeSlice(v.Type(), len(n.files), len(n.files))
+		for i, fh := range n.files {
+			if err := bindNode(s.Index(i), &formNode{key: n.key, files: []*multipart.FileHeader{fh}}); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+	}
+
+	// repeated keys, i.e. "tags=a&tags=b"
+	if len(n.values) > 0 {
+		s := reflect.MakeSlice(v.Type(), len(n.values), len(n.values))
+		for i, value := range n.values {
+			if err := bindNode(s.Index(i), &formNode{key: n.key, values: []string{value}}); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+	}
+
+	// indexed keys, i.e. "items[0][name]=a", are ordered by their index
+	var indexes []int
+	children := make(map[int]*formNode)
+	for key, c := range n.children {
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 {
+			return &BindError{Key: c.key, Value: key, Type: "index"}
+		}
+
+		indexes = append(indexes, i)
+		children[i] = c
+	}
+	sort.Ints(indexes)
+
+	for _, i := range indexes {
+		ev := reflect.New(v.Type().Elem()).Elem()
+		if err := bindNode(ev, children[i]); err != nil {
+			return err
+		}
+		v.Set(reflect.Append(v, ev))
+	}
+
+	return nil
+}
+
+func bindMap(v reflect.Value, n *formNode) error {
+	if v.Type().Key().Kind() != reflect.String {
+		return &BindError{Key: n.key, Type: v.Type().String()}
+	}
+
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(v.Type()))
+	}
+
+	for key, c := range n.children {
+		ev := reflect.New(v.Type().Elem()).Elem()
+		if err := bindNode(ev, c); err != nil {
+			return err
+		}
+		v.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), ev)
+	}
+
+	return nil
+}
+
+// assign a single form value to a string, number or bool, empty values leave
+// numbers and bools as their zero value
+func bindScalar(v reflect.Value, key, value string) error {
+	if v.Kind() == reflect.String {
+		v.SetString(value)
+		return nil
+	} else if value == "" {
+		return nil
+	}
+
+	var err error
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(value, 10, v.Type().Bits()); err == nil {
+			v.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		if u, err = strconv.ParseUint(value, 10, v.Type().Bits()); err == nil {
+			v.SetUint(u)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(value, v.Type().Bits()); err == nil {
+			v.SetFloat(f)
+		}
+	case reflect.Bool:
+		// checkboxes are submitted as "on"
+		var b bool
+		if value == "on" {
+			b = true
+		} else {
+			b, err = strconv.ParseBool(value)
+		}
+		if err == nil {
+			v.SetBool(b)
+		}
+	case reflect.Interface:
+		if v.NumMethod() == 0 {
+			v.Set(reflect.ValueOf(value))
+			return nil
+		}
+		err = fmt.Errorf("unsupported")
+	default:
+		err = fmt.Errorf("unsupported")
+	}
+
+	if err != nil {
+		return &BindError{Key: key, Value: value, Type: typeName(v.Type())}
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package legit
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURLEncoded_Match(t *testing.T) {
+	u := URLEncoded{}
+	assert.True(t, u.Match("application/x-www-form-urlencoded"))
+	assert.True(t, u.Match("application/x-www-form-urlencoded; charset=utf-8"))
+	assert.False(t, u.Match("application/json"))
+	assert.True(t, u.Match("Application/X-WWW-Form-URLEncoded"))
+	assert.False(t, u.Match("application/x-www-form-urlencodedfoo"))
+}
+
+func TestURLEncoded_TagName(t *testing.T) {
+	assert.Equal(t, "form", URLEncoded{}.TagName())
+}
+
+type formAddress struct {
+	Street Printable `form:"street"`
+	City   Alpha     `form:"city"`
+}
+
+type formBase struct {
+	ID UUID4 `form:"id"`
+}
+
+type formUser struct {
+	formBase
+	Email     Email             `form:"email"`
+	Age       Positive          `form:"age"`
+	Score     float64           `form:"score"`
+	Active    bool              `form:"active"`
+	Count     uint8             `form:"count"`
+	Tags      []Lower           `form:"tags"`
+	Address   formAddress       `form:"address"`
+	Work      *formAddress      `form:"work"`
+	Items     []formAddress     `form:"items"`
+	Meta      map[string]string `form:"meta"`
+	Born      time.Time         `form:"born"`
+	Ignored   string            `form:"-"`
+	Untagged  string
+	unexposed string
+}
+
+func TestURLEncoded_Decode(t *testing.T) {
+	body := url.Values{
+		"id":              {"625e63f3-58f5-40b7-83a1-a72ad31acffb"},
+		"email":           {"foo@example.org"},
+		"age":             {"30"},
+		"score":           {"1.5"},
+		"active":          {"on"},
+		"count":           {""},
+		"tags":            {"foo", "bar"},
+		"address[city]":   {"London"},
+		"address.street":  {"1 Foo Street"},
+		"work[city]":      {"Paris"},
+		"items[10][city]": {"Berlin"},
+		"items[2].city":   {"Rome"},
+		"meta[a]":         {"b"},
+		"born":            {"2020-01-02T03:04:05Z"},
+		"Ignored":         {"foo"},
+		"-":               {"foo"},
+		"Untagged":        {"bar"},
+		"unexposed":       {"baz"},
+	}
+
+	var user formUser
+	err := URLEncoded{}.Decode(strings.NewReader(body.Encode()), &user)
+	if assert.NoError(t, err) {
+		assert.Equal(t, formUser{
+			formBase: formBase{ID: "625e63f3-58f5-40b7-83a1-a72ad31acffb"},
+			Email:    "foo@example.org",
+			Age:      30,
+			Score:    1.5,
+			Active:   true,
+			Tags:     []Lower{"foo", "bar"},
+			Address:  formAddress{Street: "1 Foo Street", City: "London"},
+			Work:     &formAddress{City: "Paris"},
+			Items:    []formAddress{{City: "Rome"}, {City: "Berlin"}},
+			Meta:     map[string]string{"a": "b"},
+			Born:     time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+			Untagged: "bar",
+		}, user)
+	}
+}
+
+func TestURLEncoded_Decode_invalid(t *testing.T) {
+	tests := []struct {
+		Body string
+		Err  error
+	}{
+		{"age=foo", &BindError{Key: "age", Value: "foo", Type: "number"}},
+		{"count=256", &BindError{Key: "count", Value: "256", Type: "number"}},
+		{"active=maybe", &BindError{Key: "active", Value: "maybe", Type: "boolean"}},
+		{"items[foo][city]=bar", &BindError{Key: "items.foo", Value: "foo", Type: "index"}},
+		{"born=yesterday", &BindError{Key: "born", Value: "yesterday", Type: "string"}},
+	}
+
+	for _, test := range tests {
+		var user formUser
+		err := URLEncoded{}.Decode(strings.NewReader(test.Body), &user)
+		assert.Equal(t, test.Err, err, test.Body)
+	}
+
+	err := URLEncoded{}.Decode(strings.NewReader("%zz"), &formUser{})
+	assert.Error(t, err)
+
+	err = URLEncoded{}.Decode(strings.NewReader("a=b"), formUser{})
+	assert.Error(t, err)
+}
+
+func TestBindError_Error(t *testing.T) {
+	err := &BindError{Key: "age", Value: "foo", Type: "int"}
+	assert.Equal(t, `cannot bind "foo" to int field age`, err.Error())
+}
+
+func TestSplitKey(t *testing.T) {
+	assert.Equal(t, []string{"a"}, splitKey("a"))
+	assert.Equal(t, []string{"a"}, splitKey("a[]"))
+	assert.Equal(t, []string{"a", "b", "c"}, splitKey("a[b][c]"))
+	assert.Equal(t, []string{"a", "0", "b"}, splitKey("a[0].b"))
+	assert.Equal(t, []string{"[]"}, splitKey("[]"))
+}
@@ -0,0 +1,390 @@
+package legit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// walker holds the state of a single validation as it descends through a
+// value
+type walker struct {
+	l   Legit
+	ctx context.Context
+
+	// set once the context has been cancelled and validation abandoned
+	cancelled bool
+
+	// number of errors collected, and set once validation has stopped
+	// because of FailFast or MaxErrors
+	count     int
+	stopped   bool
+	truncated bool
+
+	// depth of nested structs, slices and maps currently being validated
+	depth int
+
+	// pointers, slices and maps on the path currently being validated, used
+	// to detect cycles
+	visiting map[visit]struct{}
+}
+
+// visit identifies a reference value, types are included as a pointer to a
+// struct and a pointer to its first field are otherwise indistinguishable
+type visit struct {
+	ptr uintptr
+	len int
+	typ reflect.Type
+}
+
+func (l Legit) walker(ctx context.Context) *walker {
+	return &walker{l: l, ctx: ctx}
+}
+
+// finish a validation, returning the context's error in place of any partial
+// result if validation was abandoned, marking errors as truncated if
+// MaxErrors was reached and summarizing them if SummarizeErrors is set
+func (w *walker) finish(err error) error {
+	if w.cancelled {
+		return w.ctx.Err()
+	}
+
+	errors, ok := err.(Errors)
+	if !ok {
+		return err
+	}
+
+	if w.truncated {
+		errors = append(errors, ErrTruncated)
+	}
+
+	if w.l.SummarizeErrors {
+		return SummaryError{errors}
+	}
+
+	return errors
+}
+
+// fail records an error found while validating a value, returning nil if the
+// error should be discarded because MaxErrors has already been reached
+func (w *walker) fail(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if w.l.MaxErrors > 0 && w.count >= w.l.MaxErrors {
+		w.truncated = true
+		w.stopped = true
+		return nil
+	}
+
+	w.count++
+	if w.l.FailFast {
+		w.stopped = true
+	}
+
+	return err
+}
+
+// returns the plan for a type, with fields named by the configured tag
+func (w *walker) plan(t reflect.Type) *plan {
+	return planOf(t, w.l.TagName)
+}
+
+func (w *walker) validate(objv reflect.Value, objt reflect.Type) error {
+	// don't attempt to validate pointers (optional fields) or empty
+	// interfaces
+	if (objv.Kind() == reflect.Ptr || objv.Kind() == reflect.Interface) && objv.IsNil() {
+		return nil
+	}
+
+	p := w.plan(objt)
+
+	if p.validator {
+		// calling through the address of a value avoids copying it into an
+		// interface
+		if objv.CanAddr() && p.kind != reflect.Ptr && p.kind != reflect.Interface {
+			return w.fail(w.call(objv.Addr()))
+		}
+
+		return w.fail(w.call(objv))
+	}
+
+	// types with a pointer receiver Validate method are validated through
+	// their address
+	if p.ptrValidator {
+		if w.l.StrictReceivers {
+			return w.fail(ErrPointerReceiver)
+		}
+
+		return w.fail(w.call(addressable(objv).Addr()))
+	}
+
+	switch p.kind {
+	case reflect.Struct:
+		return w.structure(objv, p)
+	case reflect.Slice, reflect.Array:
+		return w.validateSlice(objv, objt)
+	case reflect.Map:
+		return w.validateMap(objv, objt)
+	case reflect.Ptr, reflect.Interface:
+		// a reference already being validated further up the path is a cycle
+		if !w.enter(objv) {
+			return nil
+		}
+		defer w.leave(objv)
+
+		// validate the value pointed to, or the dynamic value held by the
+		// interface
+		elem := objv.Elem()
+		return w.validate(elem, elem.Type())
+	}
+
+	if w.l.Strict {
+		return w.fail(ErrStrict)
+	}
+
+	return nil
+}
+
+// call the validation method of a value, preferring ValidatorContext
+func (w *walker) call(objv reflect.Value) error {
+	switch obj := objv.Interface().(type) {
+	case ValidatorContext:
+		return obj.ValidateContext(w.ctx)
+	case Validator:
+		return obj.Validate()
+	}
+
+	return nil
+}
+
+// returns true if validation should stop, either because the context has
+// been cancelled or enough errors have been collected
+func (w *walker) done() bool {
+	if !w.cancelled && w.ctx.Err() != nil {
+		w.cancelled = true
+	}
+
+	return w.cancelled || w.stopped
+}
+
+func (w *walker) validateStruct(objv reflect.Value, objt reflect.Type) error {
+	return w.structure(objv, w.plan(objt))
+}
+
+func (w *walker) structure(objv reflect.Value, p *plan) error {
+	if err := w.descend(); err != nil {
+		return w.fail(err)
+	}
+	defer w.ascend()
+
+	errors := w.structErrors(objv, p)
+	if len(errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+// return the errors of all fields in a struct, including those promoted from
+// embedded structs
+func (w *walker) structErrors(objv reflect.Value, p *plan) Errors {
+	// fields of an addressable struct are addressable, allowing fields
+	// promoted from unexported embedded structs to be read and pointer
+	// receiver validators to be called
+	objv = addressable(objv)
+
+	var errors Errors
+
+	for _, f := range p.fields {
+		if w.done() {
+			break
+		}
+
+		// fields which cannot fail outside of strict mode are skipped
+		if !f.visit && !w.l.Strict {
+			continue
+		}
+
+		fv := objv.Field(f.index)
+
+		// embedded structs have their fields promoted, so errors are reported
+		// under the outer struct rather than the embedded type's name
+		if f.embedded {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() || !w.enter(fv) {
+					continue
+				}
+				errors = append(errors, w.structErrors(fv.Elem(), w.plan(fv.Type().Elem()))...)
+				w.leave(fv)
+				continue
+			}
+
+			errors = append(errors, w.structErrors(fv, w.plan(fv.Type()))...)
+			continue
+		}
+
+		err := w.validate(fv, fv.Type())
+		if err != nil {
+			errors = append(errors, StructError{Field: f.name, Message: err})
+		}
+	}
+
+	return errors
+}
+
+func (w *walker) validateSlice(objv reflect.Value, objt reflect.Type) error {
+	// elements which cannot fail outside of strict mode are skipped
+	if objv.Len() < 1 || (!w.l.Strict && !w.plan(objt).visitElem) {
+		return nil
+	}
+
+	if err := w.descend(); err != nil {
+		return w.fail(err)
+	}
+	defer w.ascend()
+
+	if objv.Kind() == reflect.Slice {
+		if !w.enter(objv) {
+			return nil
+		}
+		defer w.leave(objv)
+	}
+
+	var errors Errors
+
+	for i := 0; i < objv.Len() && !w.done(); i++ {
+		iv := objv.Index(i)
+		err := w.validate(iv, iv.Type())
+		if err != nil {
+			errors = append(errors, SliceError{Index: i, Message: err})
+		}
+	}
+
+	if len(errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+func (w *walker) validateMap(objv reflect.Value, objt reflect.Type) error {
+	// values which cannot fail outside of strict mode are skipped, unless
+	// keys are validators themselves
+	kp := w.plan(objt.Key())
+	validateKeys := kp.validator || kp.ptrValidator
+	if objv.Len() < 1 || (!w.l.Strict && !w.plan(objt).visitElem && !validateKeys) {
+		return nil
+	}
+
+	if err := w.descend(); err != nil {
+		return w.fail(err)
+	}
+	defer w.ascend()
+
+	if !w.enter(objv) {
+		return nil
+	}
+	defer w.leave(objv)
+
+	var errors Errors
+
+	// map iteration order is random, sort keys so errors are reported in a
+	// stable order
+	keys := objv.MapKeys()
+	sortKeys(keys)
+
+	for _, kv := range keys {
+		if w.done() {
+			break
+		}
+
+		key := fmt.Sprint(kv.Interface())
+
+		// keys are only validated when they are validators themselves,
+		// including through a pointer receiver, plain string keys should not
+		// fail strict validation
+		if validateKeys {
+			err := w.validate(kv, kv.Type())
+			if err != nil {
+				errors = append(errors, MapError{Key: key, OnKey: true, Message: err})
+			}
+		}
+
+		vv := objv.MapIndex(kv)
+		err := w.validate(vv, vv.Type())
+		if err != nil {
+			errors = append(errors, MapError{Key: key, Message: err})
+		}
+	}
+
+	if len(errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
+
+// descend into a nested struct, slice or map, returning an error if the
+// maximum depth has been reached
+func (w *walker) descend() error {
+	if w.l.MaxDepth > 0 && w.depth >= w.l.MaxDepth {
+		return DepthError{MaxDepth: w.l.MaxDepth}
+	}
+
+	w.depth++
+	return nil
+}
+
+func (w *walker) ascend() {
+	w.depth--
+}
+
+// mark a pointer, slice or map as being validated, returning false if it is
+// already being validated further up the path
+func (w *walker) enter(objv reflect.Value) bool {
+	k, ok := visitOf(objv)
+	if !ok {
+		return true
+	}
+
+	if _, seen := w.visiting[k]; seen {
+		return false
+	}
+
+	if w.visiting == nil {
+		w.visiting = make(map[visit]struct{})
+	}
+	w.visiting[k] = struct{}{}
+
+	return true
+}
+
+// resolve the pointers to the value being validated, entering each so a value
+// referring back to itself is only validated once
+func (w *walker) resolve(objv reflect.Value) reflect.Value {
+	for objv.Kind() == reflect.Ptr && !objv.IsNil() {
+		w.enter(objv)
+		objv = objv.Elem()
+	}
+
+	return resolvePointer(objv)
+}
+
+func (w *walker) leave(objv reflect.Value) {
+	if k, ok := visitOf(objv); ok {
+		delete(w.visiting, k)
+	}
+}
+
+func visitOf(objv reflect.Value) (visit, bool) {
+	switch objv.Kind() {
+	case reflect.Ptr, reflect.Map:
+		return visit{ptr: objv.Pointer(), typ: objv.Type()}, true
+	case reflect.Slice:
+		return visit{ptr: objv.Pointer(), len: objv.Len(), typ: objv.Type()}, true
+	}
+
+	return visit{}, false
+}
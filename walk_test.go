@@ -0,0 +1,170 @@
+package legit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type node struct {
+	Name     Lower
+	Parent   *node
+	Children []*node
+}
+
+func TestWalker_validate_pointer(t *testing.T) {
+	err := legit.walker(context.Background()).validate(reflected(struct {
+		Node *node
+	}{&node{Name: "FOO"}}))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{StructError{Field: "Node", Message: Errors{StructError{Field: "Name", Message: ErrLower}}}}, err)
+	}
+}
+
+func TestWalker_validate_pointerValidator(t *testing.T) {
+	name := Lower("FOO")
+	err := legit.walker(context.Background()).validate(reflected(struct {
+		Name *Lower
+		Any  Validator
+	}{&name, Lower("BAR")}))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{StructError{Field: "Name", Message: ErrLower}, StructError{Field: "Any", Message: ErrLower}}, err)
+	}
+}
+
+func TestWalker_validate_cycle(t *testing.T) {
+	root := &node{Name: "root"}
+	child := &node{Name: "CHILD", Parent: root}
+	root.Children = []*node{child}
+	root.Parent = root
+
+	err := legit.walker(context.Background()).validate(reflected(root))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{StructError{Field: "Children", Message: Errors{
+			SliceError{Index: 0, Message: Errors{StructError{Field: "Name", Message: ErrLower}}},
+		}}}, err)
+	}
+
+	m := map[string]interface{}{"name": Lower("FOO")}
+	m["self"] = m
+	err = legit.walker(context.Background()).validate(reflected(m))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{MapError{Key: "name", Message: ErrLower}}, err)
+	}
+
+	s := []interface{}{nil, Lower("FOO")}
+	s[0] = s
+	err = legit.walker(context.Background()).validate(reflected(s))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{SliceError{Index: 1, Message: ErrLower}}, err)
+	}
+}
+
+type embeddedCycle struct {
+	*embeddedCycle
+	Name Lower
+}
+
+type linkedEmail struct {
+	Val  Email
+	Next *linkedEmail
+}
+
+func TestWalker_structErrors_cycle(t *testing.T) {
+	v := &embeddedCycle{Name: "FOO"}
+	v.embeddedCycle = v
+
+	// the root pointer is being validated, so the cycle back to it is not
+	// validated again
+	err := Validate(v)
+	assert.Equal(t, Errors{StructError{Field: "Name", Message: ErrLower}}, err)
+
+	a := &linkedEmail{Val: "foo"}
+	a.Next = a
+	assert.Equal(t, []FieldError{{Path: Path{"Val"}, Message: ErrEmail}}, Flatten(Validate(a)))
+	assert.Equal(t, Errors{StructError{Field: "Val", Message: ErrEmail}}, ValidateStruct(a))
+}
+
+func TestWalker_validate_maxDepth(t *testing.T) {
+	l := Legit{MaxDepth: 2}
+
+	err := l.walker(context.Background()).validate(reflected([][]Lower{{"foo"}}))
+	assert.NoError(t, err)
+
+	err = l.walker(context.Background()).validate(reflected([][][]Lower{{{"foo"}}}))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{SliceError{Index: 0, Message: Errors{SliceError{Index: 0, Message: DepthError{MaxDepth: 2}}}}}, err)
+	}
+
+	root := &node{Name: "root"}
+	for i := 0; i < 10; i++ {
+		root = &node{Name: "node", Children: []*node{root}}
+	}
+	err = l.walker(context.Background()).validate(reflected(root))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{StructError{Field: "Children", Message: Errors{SliceError{Index: 0, Message: DepthError{MaxDepth: 2}}}}}, err)
+	}
+}
+
+func TestLegit_Validate_failFast(t *testing.T) {
+	l := Legit{FailFast: true}
+
+	err := l.Validate(struct {
+		Names []Lower
+		Email Email
+	}{[]Lower{"foo", "FOO", "BAR"}, "foo"})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{StructError{Field: "Names", Message: Errors{SliceError{Index: 1, Message: ErrLower}}}}, err)
+		assert.False(t, err.(Errors).Truncated())
+	}
+}
+
+func TestLegit_Validate_maxErrors(t *testing.T) {
+	l := Legit{MaxErrors: 2}
+
+	err := l.Validate([]Lower{"FOO", "foo", "BAR"})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{SliceError{Index: 0, Message: ErrLower}, SliceError{Index: 2, Message: ErrLower}}, err)
+		assert.False(t, err.(Errors).Truncated())
+	}
+
+	err = l.Validate([]Lower{"FOO", "foo", "BAR", "BAZ", "QUX"})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{SliceError{Index: 0, Message: ErrLower}, SliceError{Index: 2, Message: ErrLower}, ErrTruncated}, err)
+		assert.True(t, err.(Errors).Truncated())
+	}
+
+	err = l.ValidateStruct(struct {
+		First []Lower
+		Last  Lower
+	}{[]Lower{"FOO", "BAR"}, "BAZ"})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, Errors{StructError{Field: "First", Message: Errors{SliceError{Index: 0, Message: ErrLower}, SliceError{Index: 1, Message: ErrLower}}}, ErrTruncated}, err)
+	}
+}
+
+func TestLegit_Validate_summarizeErrors(t *testing.T) {
+	l := Legit{SummarizeErrors: true, MaxErrors: 2}
+
+	err := l.Validate(struct {
+		Names []Lower
+		Email Email
+		Other Lower
+	}{[]Lower{"foo", "FOO"}, "foo", "BAR"})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, SummaryError{Errors{
+			StructError{Field: "Names", Message: Errors{SliceError{Index: 1, Message: ErrLower}}},
+			StructError{Field: "Email", Message: ErrEmail},
+			ErrTruncated,
+		}}, err)
+		assert.EqualError(t, err, "Names[1]: string is not lowercase; Email: invalid email; too many errors")
+		assert.Equal(t, "summary: Names[1]: string is not lowercase; Email: invalid email; too many errors", fmt.Sprintf("summary: %v", err))
+		assert.True(t, errors.Is(err, ErrEmail))
+		assert.Len(t, Flatten(err), 2)
+	}
+
+	assert.NoError(t, l.Validate(struct{ Name Lower }{"foo"}))
+}
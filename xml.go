@@ -0,0 +1,358 @@
+package legit
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ErrDOCTYPE is returned by the XML decoder when DisallowDOCTYPE is set and a
+// body contains a DOCTYPE declaration.
+var ErrDOCTYPE = errors.New("xml: DOCTYPE declaration not allowed")
+
+// UnknownElementError is returned by the XML decoder when
+// DisallowUnknownElements is set and an element does not match any field.
+// Path is the location of the element, ending with its name.
+type UnknownElementError struct {
+	Element string
+	Path    Path
+	Offset  int64
+}
+
+// returns the string representation of the unknown element
+func (ue *UnknownElementError) Error() string {
+	return fmt.Sprintf("xml: unknown element %q at offset %d", ue.Path, ue.Offset)
+}
+
+// xmlFrame is an element currently being scanned
+type xmlFrame struct {
+	name string
+
+	// number of child elements seen of each name
+	children map[string]int
+
+	// the fields which may be decoded from the element, nil if any element is
+	// accepted
+	node *xmlNode
+}
+
+// xmlCharset transcodes an XML body from the encoding declared by its prolog,
+// unless it has already been transcoded, recording any error
+type xmlCharset struct {
+	converted bool
+	err       error
+}
+
+func (c *xmlCharset) reader(label string, r io.Reader) (io.Reader, error) {
+	if c.converted {
+		return r, nil
+	}
+
+	rd, err := charsetReader(label, r)
+	c.err = err
+	return rd, err
+}
+
+// returns a decoder of an XML body, which is UTF-8 if converted is set
+func (x XML) newDecoder(r io.Reader, converted bool) (*xml.Decoder, *xmlCharset) {
+	cs := &xmlCharset{converted: converted}
+
+	dec := xml.NewDecoder(r)
+	dec.CharsetReader = cs.reader
+
+	if x.Lenient {
+		dec.Strict = false
+		dec.AutoClose = xml.HTMLAutoClose
+		dec.Entity = xml.HTMLEntity
+	}
+
+	return dec, cs
+}
+
+// returns true if a body must be scanned before it is decoded
+func (x XML) scans() bool {
+	return x.Limits.enabled() || x.MaxTokens > 0 || x.MaxAttributes > 0 || x.DisallowDOCTYPE || x.DisallowUnknownElements
+}
+
+// scan returns an error if b exceeds the limits of the decoder, or contains a
+// DOCTYPE declaration or unknown element when disallowed. Malformed XML is
+// ignored, and reported when decoding.
+func (x XML) scan(b []byte, converted bool, dst interface{}) error {
+	dec, _ := x.newDecoder(bytes.NewReader(b), converted)
+
+	var (
+		stack  []*xmlFrame
+		tokens int
+	)
+
+	for {
+		tok, err := dec.RawToken()
+		if err != nil {
+			return nil
+		}
+
+		tokens++
+		if x.MaxTokens > 0 && tokens > x.MaxTokens {
+			return &LimitError{Err: ErrTooManyTokens, Max: int64(x.MaxTokens), Path: xmlPath(stack), Offset: dec.InputOffset()}
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			path := append(xmlPath(stack), t.Name.Local)
+			if len(stack) < 1 {
+				path = Path{}
+			}
+
+			if x.MaxDepth > 0 && len(stack) >= x.MaxDepth {
+				return &LimitError{Err: ErrTooDeep, Max: int64(x.MaxDepth), Path: path, Offset: dec.InputOffset()}
+			}
+
+			if x.MaxAttributes > 0 && len(t.Attr) > x.MaxAttributes {
+				return &LimitError{Err: ErrTooManyAttributes, Max: int64(x.MaxAttributes), Path: path, Offset: dec.InputOffset()}
+			}
+
+			var node *xmlNode
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children[t.Name.Local]++
+				if x.MaxArrayLength > 0 && parent.children[t.Name.Local] > x.MaxArrayLength {
+					return &LimitError{Err: ErrArrayTooLong, Max: int64(x.MaxArrayLength), Path: path, Offset: dec.InputOffset()}
+				}
+
+				if parent.node != nil {
+					var ok bool
+					if node, ok = parent.node.child(t.Name.Local); !ok && x.DisallowUnknownElements {
+						return &UnknownElementError{Element: t.Name.Local, Path: path, Offset: dec.InputOffset()}
+					}
+				}
+			} else if x.DisallowUnknownElements {
+				node = xmlNodeOf(reflect.TypeOf(dst))
+			}
+
+			stack = append(stack, &xmlFrame{name: t.Name.Local, children: make(map[string]int), node: node})
+
+			for _, attr := range t.Attr {
+				if x.MaxStringLength > 0 && utf8.RuneCountInString(attr.Value) > x.MaxStringLength {
+					return &LimitError{Err: ErrStringTooLong, Max: int64(x.MaxStringLength), Path: append(xmlPath(stack), attr.Name.Local), Offset: dec.InputOffset()}
+				}
+			}
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			if x.MaxStringLength > 0 && utf8.RuneCount(t) > x.MaxStringLength {
+				return &LimitError{Err: ErrStringTooLong, Max: int64(x.MaxStringLength), Path: xmlPath(stack), Offset: dec.InputOffset()}
+			}
+		case xml.Directive:
+			if x.DisallowDOCTYPE && bytes.HasPrefix(bytes.TrimSpace(t), []byte("DOCTYPE")) {
+				return ErrDOCTYPE
+			}
+		}
+	}
+}
+
+// returns the path of element names to the innermost element, excluding the
+// root element which is not named by the fields of a struct
+func xmlPath(stack []*xmlFrame) Path {
+	path := Path{}
+	for i, f := range stack {
+		if i > 0 {
+			path = append(path, f.name)
+		}
+	}
+
+	return path
+}
+
+// trailingXML returns a TrailingDataError if anything other than whitespace,
+// comments or processing instructions remain after a document
+func trailingXML(dec *xml.Decoder) error {
+	for {
+		offset := dec.InputOffset()
+
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return &TrailingDataError{Offset: offset}
+		}
+
+		switch t := tok.(type) {
+		case xml.Comment, xml.ProcInst:
+			continue
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) < 1 {
+				continue
+			}
+		}
+
+		return &TrailingDataError{Offset: offset}
+	}
+}
+
+// xmlNode describes the elements which may be decoded into a type. prefix is
+// set for the elements of a nested field path, such as "a>b".
+type xmlNode struct {
+	typ    reflect.Type
+	prefix []string
+}
+
+// xmlField is a field of a struct decoded from a child element
+type xmlField struct {
+	path []string
+	typ  reflect.Type
+}
+
+// xmlFields are the child elements of a struct type, any is set if the
+// struct accepts all elements
+type xmlFields struct {
+	fields []xmlField
+	any    bool
+}
+
+var (
+	xmlUnmarshaler = reflect.TypeOf((*xml.Unmarshaler)(nil)).Elem()
+	xmlNameType    = reflect.TypeOf(xml.Name{})
+	xmlFieldCache  sync.Map
+)
+
+// returns the node of a destination type, or nil if it accepts any element
+func xmlNodeOf(t reflect.Type) *xmlNode {
+	t = xmlElemType(t)
+	if t.Kind() == reflect.Interface || reflect.PtrTo(t).Implements(xmlUnmarshaler) {
+		return nil
+	}
+
+	if t.Kind() == reflect.Struct && !reflect.PtrTo(t).Implements(textUnmarshaler) && xmlFieldsOf(t).any {
+		return nil
+	}
+
+	return &xmlNode{typ: t}
+}
+
+// returns the node of a child element, and false if it is unknown
+func (n *xmlNode) child(name string) (*xmlNode, bool) {
+	if n.typ.Kind() != reflect.Struct || reflect.PtrTo(n.typ).Implements(textUnmarshaler) {
+		return nil, false
+	}
+
+	depth := len(n.prefix)
+	for _, f := range xmlFieldsOf(n.typ).fields {
+		if len(f.path) <= depth || f.path[depth] != name || !hasPrefix(f.path, n.prefix) {
+			continue
+		}
+
+		if len(f.path) == depth+1 {
+			return xmlNodeOf(f.typ), true
+		}
+
+		return &xmlNode{typ: n.typ, prefix: f.path[:depth+1]}, true
+	}
+
+	return nil, false
+}
+
+func hasPrefix(path, prefix []string) bool {
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// returns the type decoded from an element into a field, dereferencing
+// pointers and the elements of slices
+func xmlElemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+		t = t.Elem()
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+
+	return t
+}
+
+// returns the child elements of a struct, in the same way as encoding/xml
+func xmlFieldsOf(t reflect.Type) *xmlFields {
+	if xf, ok := xmlFieldCache.Load(t); ok {
+		return xf.(*xmlFields)
+	}
+
+	xf := &xmlFields{}
+
+	for i := 0; i < t.NumField(); i++ {
+		ft := t.Field(i)
+		tag, hasTag := ft.Tag.Lookup("xml")
+		if tag == "-" || ft.Type == xmlNameType {
+			continue
+		}
+
+		// untagged embedded structs have their fields promoted
+		if ft.Anonymous && !hasTag {
+			et := ft.Type
+			if et.Kind() == reflect.Ptr {
+				et = et.Elem()
+			}
+			if et.Kind() == reflect.Struct {
+				embedded := xmlFieldsOf(et)
+				xf.fields = append(xf.fields, embedded.fields...)
+				xf.any = xf.any || embedded.any
+				continue
+			}
+		}
+
+		if ft.PkgPath != "" {
+			continue
+		}
+
+		name, opts := tag, ""
+		if i := strings.Index(tag, ","); i >= 0 {
+			name, opts = tag[:i], tag[i+1:]
+		}
+
+		if hasOption(opts, "any") || hasOption(opts, "innerxml") {
+			xf.any = true
+			continue
+		} else if opts != "" && !hasOption(opts, "omitempty") {
+			// attributes, character data and comments are not elements
+			continue
+		}
+
+		// discard the namespace of a name
+		if i := strings.LastIndex(name, " "); i >= 0 {
+			name = name[i+1:]
+		}
+		if name == "" {
+			name = ft.Name
+		}
+
+		xf.fields = append(xf.fields, xmlField{path: strings.Split(name, ">"), typ: ft.Type})
+	}
+
+	xmlFieldCache.Store(t, xf)
+	return xf
+}
+
+func hasOption(opts, opt string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == opt {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,122 @@
+package legit
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type xmlBase struct {
+	ID string `xml:"id"`
+}
+
+type xmlItem struct {
+	SKU string `xml:"sku,attr"`
+	Qty int    `xml:"qty"`
+}
+
+type xmlOrder struct {
+	XMLName xml.Name `xml:"order"`
+	xmlBase
+	Customer string    `xml:"urn:example customer"`
+	City     string    `xml:"address>city"`
+	Items    []xmlItem `xml:"items>item"`
+	Notes    *string   `xml:",omitempty"`
+	Text     string    `xml:",chardata"`
+	Ignored  string    `xml:"-"`
+	internal string
+}
+
+type xmlEnvelope struct {
+	XMLName xml.Name `xml:"Envelope"`
+	Body    struct {
+		Content string `xml:",innerxml"`
+	} `xml:"Body"`
+}
+
+func TestXML_Decode_hardening(t *testing.T) {
+	order := `<order><id>1</id><customer xmlns="urn:example">foo</customer><address><city>London</city></address>` +
+		`<items><item sku="a"><qty>1</qty></item><item sku="b"><qty>2</qty></item></items><Notes>n</Notes></order>`
+
+	tests := []struct {
+		Name string
+		XML  XML
+		Body string
+		Err  error
+	}{
+		{"Valid", XML{MaxTokens: 64, MaxAttributes: 1, DisallowDOCTYPE: true, DisallowUnknownElements: true, DisallowTrailingData: true}, order, nil},
+		{"Tokens", XML{MaxTokens: 4}, order, &LimitError{Err: ErrTooManyTokens, Max: 4, Path: Path{}, Offset: 47}},
+		{"Attributes", XML{MaxAttributes: 1}, `<order><items><item sku="a" x="b"></item></items></order>`, &LimitError{Err: ErrTooManyAttributes, Max: 1, Path: Path{"items", "item"}, Offset: 34}},
+		{"RootAttributes", XML{MaxAttributes: 1}, `<order a="1" b="2"></order>`, &LimitError{Err: ErrTooManyAttributes, Max: 1, Path: Path{}, Offset: 19}},
+		{"DOCTYPEAllowed", XML{}, `<!DOCTYPE order><order></order>`, nil},
+		{"DOCTYPE", XML{DisallowDOCTYPE: true}, `<!DOCTYPE order [<!ENTITY a "b">]><order></order>`, ErrDOCTYPE},
+		{"Unknown", XML{DisallowUnknownElements: true}, `<order><id>1</id><name>foo</name></order>`, &UnknownElementError{Element: "name", Path: Path{"name"}, Offset: 23}},
+		{"UnknownNested", XML{DisallowUnknownElements: true}, `<order><items><item><qty>1</qty><price>2</price></item></items></order>`, &UnknownElementError{Element: "price", Path: Path{"items", "item", "price"}, Offset: 39}},
+		{"UnknownPath", XML{DisallowUnknownElements: true}, `<order><address><street>foo</street></address></order>`, &UnknownElementError{Element: "street", Path: Path{"address", "street"}, Offset: 24}},
+		{"UnknownLeaf", XML{DisallowUnknownElements: true}, `<order><id><value>1</value></id></order>`, &UnknownElementError{Element: "value", Path: Path{"id", "value"}, Offset: 18}},
+		{"UnknownIgnored", XML{DisallowUnknownElements: true}, `<order><Ignored>1</Ignored></order>`, &UnknownElementError{Element: "Ignored", Path: Path{"Ignored"}, Offset: 16}},
+		{"UnknownAllowed", XML{}, `<order><name>foo</name></order>`, nil},
+		{"Trailing", XML{DisallowTrailingData: true}, `<order></order><order></order>`, &TrailingDataError{Offset: 15}},
+		{"TrailingText", XML{DisallowTrailingData: true}, `<order></order> foo`, &TrailingDataError{Offset: 15}},
+		{"TrailingComment", XML{DisallowTrailingData: true}, "<order></order>\n<!-- foo --><?pi?>\n", nil},
+		{"TrailingAllowed", XML{}, `<order></order><order></order>`, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var o xmlOrder
+			err := test.XML.Decode(strings.NewReader(test.Body), &o)
+			if test.Err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, test.Err, err)
+			}
+		})
+	}
+}
+
+func TestXML_Decode_unknownElementsAny(t *testing.T) {
+	x := XML{DisallowUnknownElements: true}
+
+	var env xmlEnvelope
+	err := x.Decode(strings.NewReader(`<Envelope><Body><foo><bar/></foo></Body></Envelope>`), &env)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "<foo><bar/></foo>", env.Body.Content)
+	}
+
+	err = x.Decode(strings.NewReader(`<Envelope><Header/><Body/></Envelope>`), &env)
+	assert.Equal(t, &UnknownElementError{Element: "Header", Path: Path{"Header"}, Offset: 19}, err)
+
+	var any struct {
+		Any []struct {
+			XMLName xml.Name
+		} `xml:",any"`
+	}
+	assert.NoError(t, x.Decode(strings.NewReader(`<root><a/><b><c/></b></root>`), &any))
+}
+
+func TestXML_Decode_lenient(t *testing.T) {
+	body := `<order><id>1&nbsp;</id><customer xmlns=urn:example>foo</customer></order>`
+
+	var o xmlOrder
+	assert.Error(t, XML{}.Decode(strings.NewReader(body), &o))
+
+	if assert.NoError(t, XML{Lenient: true}.Decode(strings.NewReader(body), &o)) {
+		assert.Equal(t, "1 ", o.ID)
+	}
+}
+
+func TestUnknownElementError_Error(t *testing.T) {
+	err := &UnknownElementError{Element: "price", Path: Path{"items", "item", "price"}, Offset: 39}
+	assert.EqualError(t, err, `xml: unknown element "items.item.price" at offset 39`)
+
+	assert.Equal(t, Errors{
+		StructError{Field: "items", Message: Errors{
+			StructError{Field: "item", Message: Errors{
+				StructError{Field: "price", Message: ErrDecodeUnknownField},
+			}},
+		}},
+	}, decodeErrors(err))
+}